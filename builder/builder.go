@@ -1,29 +1,136 @@
 package builder
 
 import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/lucasvillarinho/restql/parser"
 )
 
+// bufferPool reuses SQL buffers across builds.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool.
+func putBuffer(buf *bytes.Buffer) {
+	bufferPool.Put(buf)
+}
+
+// Logger receives debug logs of built queries and warnings for rejected
+// queries, as a message followed by alternating keys and values.
+// *slog.Logger satisfies it; adapt other loggers with a small wrapper.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Warn(msg string, args ...any)
+}
+
 // QueryBuilder builds SQL queries from parsed filter expressions.
 type QueryBuilder struct {
 	table            string
+	tableAlias       string
+	joins            []join
+	relations        []relation
+	rawWhere         *rawWhere
+	inSubqueries     []inSubquery
+	inConditions     []inCondition
+	after            []any // Keyset values of the last row, from SetCursor
 	fields           []string
+	distinctOn       []string // Fields of a Postgres SELECT DISTINCT ON (...)
+	ensuredFields    []string // Fields always selected when fields are listed
+	groupBy          []string
+	groupRollup      bool // Emit GROUP BY as ROLLUP(...) for subtotals
 	filter           *parser.Filter
 	sort             []string
+	defaultSort      []string
+	sortCollations   map[string]string   // Collation emitted for each sort field
+	sortOrders       map[string][]string // Values in custom sort order for each field
+	jsonColumns      map[string]bool     // Columns whose dotted fields are JSON paths
 	limit            int
 	offset           int
 	args             []any
-	placeholderStyle string // Placeholder style: "?", "$1", ":1", etc.
-	placeholderCount int    // Counter for numbered placeholders
+	kinds            []parser.ValueKind // Kinds of args, in the same order
+	placeholderStyle string             // Placeholder style: "?", "$1", ":1", ":p0", etc.
+	placeholderCount int                // Counter for numbered placeholders
+	placeholderStart int                // Numbered placeholders begin at placeholderStart+1
+	barePredicates   bool               // Treat bare fields as boolean predicates (field = true)
+	tildeILike       bool               // Emit ~ and !~ as ILIKE / NOT ILIKE instead of LIKE / NOT LIKE
+	dialect          Dialect
+	dateStrings      bool                        // Bind date literals as normalized strings instead of time.Time
+	minimalParens    bool                        // Omit redundant outermost parentheses in WHERE
+	foldOrEquals     bool                        // Fold same-field OR equalities into IN
+	arrayParams      bool                        // Bind IN lists as a single array (Postgres ANY/ALL)
+	nullSafeNotIn    bool                        // Keep NULL rows in NOT IN (field NOT IN (...) OR field IS NULL)
+	quoteIdentifiers bool                        // Quote table and field names with the dialect's quote character
+	fieldTypes       map[string]parser.ValueKind // Declared field types used to coerce quoted numbers
+	caseFold         map[string]bool             // Fields compared case-insensitively with = and !=
+	inline           bool                        // Render values as literals instead of placeholders
+	logger           Logger                      // Receives built queries and validation rejections
+	err              error                       // First error encountered while building
+}
+
+// join represents an INNER JOIN declaration.
+type join struct {
+	table string
+	on    string
+}
+
+// relation represents a one-to-many relationship filtered with EXISTS.
+type relation struct {
+	table string
+	on    string
+}
+
+// rawWhere represents a hand-written predicate with its arguments.
+type rawWhere struct {
+	sql  string
+	args []any
+}
+
+// LimitAll is the limit requested by "limit=all" or "limit=-1": no LIMIT
+// clause. Validation rejects it unless WithUnboundedLimit is set.
+const LimitAll = -1
+
+// mysqlMaxLimit is the LIMIT MySQL documents for "all remaining rows" when
+// only an OFFSET is wanted.
+const mysqlMaxLimit = "18446744073709551615"
+
+// inSubquery represents a "field IN (subquery)" predicate.
+type inSubquery struct {
+	field string
+	sub   rawWhere
+}
+
+// inCondition represents a "field IN (values)" predicate added in code.
+type inCondition struct {
+	field  string
+	values []any
 }
 
+// namedPlaceholder is the placeholder style that emits :p0, :p1, ... tokens.
+const namedPlaceholder = ":p0"
+
 // NewQueryBuilder creates a new query builder for the given table.
+// Surrounding whitespace is trimmed from the table name; an empty or invalid
+// name (e.g. "users; --") is reported by ToSQL.
 func NewQueryBuilder(table string) *QueryBuilder {
 	return &QueryBuilder{
-		table:            table,
+		table:            strings.TrimSpace(table),
 		args:             make([]any, 0),
 		placeholderStyle: "?", // Default to MySQL/SQLite style
 	}
@@ -35,15 +142,137 @@ func (qb *QueryBuilder) SetPlaceholder(style string) *QueryBuilder {
 	return qb
 }
 
+// SetPlaceholderStart offsets numbered placeholders so they begin at n+1
+// (e.g. $3, $4, ... for n=2). Use it to splice the generated clause into a
+// larger statement that already binds n arguments. Positional "?" is unaffected.
+func (qb *QueryBuilder) SetPlaceholderStart(n int) *QueryBuilder {
+	qb.placeholderStart = n
+	return qb
+}
+
 // getPlaceholder returns the next placeholder string based on the configured style.
 func (qb *QueryBuilder) getPlaceholder() string {
+	// Every placeholder directly follows the addArg of its value
+	if qb.inline {
+		return qb.dialect.literal(qb.args[len(qb.args)-1])
+	}
+
 	if qb.placeholderStyle == "?" {
 		return "?"
 	}
 
 	// For numbered placeholders like $1, $2, ... or :1, :2, ...
 	qb.placeholderCount++
-	return fmt.Sprintf("%s%d", qb.placeholderStyle[:1], qb.placeholderCount)
+	return qb.numberedPlaceholder(qb.placeholderCount)
+}
+
+// numberedPlaceholder returns the n-th (1-based) numbered placeholder.
+func (qb *QueryBuilder) numberedPlaceholder(n int) string {
+	// For named placeholders like :p0, :p1, ... (zero-based)
+	if qb.placeholderStyle == namedPlaceholder {
+		return fmt.Sprintf(":p%d", n-1)
+	}
+
+	// The style's prefix is everything before its trailing number ("$1" -> "$",
+	// "@p1" -> "@p")
+	return fmt.Sprintf("%s%d", strings.TrimRight(qb.placeholderStyle, "0123456789"), n)
+}
+
+// SetDialect sets the SQL dialect for this query builder.
+func (qb *QueryBuilder) SetDialect(dialect Dialect) *QueryBuilder {
+	qb.dialect = dialect
+	return qb
+}
+
+// SetDateStrings controls how date literals are bound.
+// By default they are bound as time.Time; when enabled, they are bound as
+// normalized strings (YYYY-MM-DD or RFC 3339).
+func (qb *QueryBuilder) SetDateStrings(enabled bool) *QueryBuilder {
+	qb.dateStrings = enabled
+	return qb
+}
+
+// SetMinimalParens enables or disables minimal parentheses.
+// When enabled, the redundant outermost parentheses of the WHERE clause are
+// omitted (e.g. "WHERE age > ? AND status = ?"). Nested groups keep theirs.
+func (qb *QueryBuilder) SetMinimalParens(enabled bool) *QueryBuilder {
+	qb.minimalParens = enabled
+	return qb
+}
+
+// SetFieldTypes declares the types of fields. Quoted numeric values compared
+// with a field declared as parser.KindInt or parser.KindFloat are bound as
+// numbers (e.g. age='18' binds int 18); other values are bound as parsed.
+func (qb *QueryBuilder) SetFieldTypes(types map[string]parser.ValueKind) *QueryBuilder {
+	qb.fieldTypes = types
+	return qb
+}
+
+// SetQuoteIdentifiers enables or disables identifier quoting.
+// When enabled, the table, alias and field names are quoted with the
+// dialect's quote character: backticks for MySQL and ClickHouse, brackets for
+// SQL Server, double quotes otherwise (e.g. "users"."id"). Joins and raw SQL are left as written.
+func (qb *QueryBuilder) SetQuoteIdentifiers(enabled bool) *QueryBuilder {
+	qb.quoteIdentifiers = enabled
+	return qb
+}
+
+// SetLogger sets a logger that receives each built query and its args at
+// debug level. Validators of this query log rejections at warn level.
+func (qb *QueryBuilder) SetLogger(logger Logger) *QueryBuilder {
+	qb.logger = logger
+	return qb
+}
+
+// logQuery logs a built query when a logger is set.
+func (qb *QueryBuilder) logQuery(sql string, args []any) {
+	if qb.logger != nil {
+		qb.logger.Debug("restql: built query", "table", qb.table, "sql", sql, "args", args)
+	}
+}
+
+// SetFoldOrEquals enables or disables folding of same-field OR equalities.
+// When enabled, "status='a' || status='b'" and "status IN ('a','b') ||
+// status='c'" are emitted as a single IN with repeated values dropped, e.g.
+// "status IN (?, ?, ?)". OR branches on other fields are left untouched, and
+// so are case-folded fields (see SetCaseFold).
+func (qb *QueryBuilder) SetFoldOrEquals(enabled bool) *QueryBuilder {
+	qb.foldOrEquals = enabled
+	return qb
+}
+
+// SetArrayParams enables or disables array parameters for IN lists.
+// When enabled with the Postgres dialect, IN and NOT IN are emitted as
+// "field = ANY($1)" and "field != ALL($1)" with the values bound as a single
+// []any argument. Other dialects keep expanded placeholders.
+func (qb *QueryBuilder) SetArrayParams(enabled bool) *QueryBuilder {
+	qb.arrayParams = enabled
+	return qb
+}
+
+// SetNullSafeNotIn enables or disables NULL-safe NOT IN. In standard SQL,
+// "status NOT IN (?, ?)" never matches rows where status is NULL; when
+// enabled, it is emitted as "(status NOT IN (?, ?) OR status IS NULL)" so
+// those rows are kept. Lists with an explicit null are left as written.
+func (qb *QueryBuilder) SetNullSafeNotIn(enabled bool) *QueryBuilder {
+	qb.nullSafeNotIn = enabled
+	return qb
+}
+
+// SetBarePredicates enables or disables bare boolean predicates.
+// When enabled, a bare field (e.g. "active") is emitted as "active = ?" with
+// arg true, and a negated field (e.g. "!active") with arg false.
+func (qb *QueryBuilder) SetBarePredicates(enabled bool) *QueryBuilder {
+	qb.barePredicates = enabled
+	return qb
+}
+
+// SetTildeILike makes "~" and "!~" case-insensitive: "name~'jo%'" is emitted
+// as ILIKE (or its LOWER(...) LIKE translation outside Postgres) instead of
+// LIKE, matching APIs that use "~" for case-insensitive matching.
+func (qb *QueryBuilder) SetTildeILike(enabled bool) *QueryBuilder {
+	qb.tildeILike = enabled
+	return qb
 }
 
 // Validate creates a validator for this query with the given options.
@@ -61,12 +290,201 @@ func (qb *QueryBuilder) Validate(opts ...ValidateOption) *Validator {
 	return v
 }
 
+// SetTableAlias sets an alias for the table, emitted as "FROM users AS u".
+// Unqualified fields in filters, selects, and sorts are prefixed with the
+// alias (e.g. "age" becomes "u.age"); already-qualified fields are untouched.
+func (qb *QueryBuilder) SetTableAlias(alias string) *QueryBuilder {
+	qb.tableAlias = alias
+	return qb
+}
+
+// SetJSONColumns declares JSON columns. A field starting with a JSON column
+// (e.g. "data.country" or "data.address.city") is emitted as the dialect's
+// path extraction: data->>'country' for Postgres, JSON_VALUE for Oracle and
+// JSON_EXTRACT(data, '$.country') otherwise. Values stay parameterized.
+func (qb *QueryBuilder) SetJSONColumns(columns ...string) *QueryBuilder {
+	qb.jsonColumns = make(map[string]bool, len(columns))
+	for _, column := range columns {
+		qb.jsonColumns[column] = true
+	}
+	return qb
+}
+
+// jsonPath splits a field into a declared JSON column and its path keys.
+func (qb *QueryBuilder) jsonPath(field string) (string, []string, bool) {
+	column, path, ok := strings.Cut(field, ".")
+	if !ok || !qb.jsonColumns[column] {
+		return "", nil, false
+	}
+	return column, strings.Split(path, "."), true
+}
+
+// column returns the SQL expression for a field: a JSON path extraction for
+// fields of JSON columns, otherwise the qualified field.
+func (qb *QueryBuilder) column(field string) string {
+	if column, path, ok := qb.jsonPath(field); ok {
+		return qb.dialect.jsonExtract(qb.qualify(column), path)
+	}
+	return qb.qualify(field)
+}
+
+// qualify prefixes an unqualified field with the table alias, if one is set,
+// and quotes it when identifier quoting is enabled.
+func (qb *QueryBuilder) qualify(field string) string {
+	if qb.tableAlias != "" && !strings.Contains(field, ".") {
+		field = qb.tableAlias + "." + field
+	}
+	return qb.ident(field)
+}
+
+// ident quotes an identifier when identifier quoting is enabled.
+func (qb *QueryBuilder) ident(name string) string {
+	if !qb.quoteIdentifiers {
+		return name
+	}
+	return qb.dialect.quote(name)
+}
+
+// InnerJoin declares an INNER JOIN with the given table and ON condition.
+// Fields of joined tables can be referenced qualified, e.g. "profiles.verified".
+//
+// Example:
+//
+//	qb.InnerJoin("profiles", "users.id = profiles.user_id")
+func (qb *QueryBuilder) InnerJoin(table, on string) *QueryBuilder {
+	qb.joins = append(qb.joins, join{table: table, on: on})
+	return qb
+}
+
+// HasMany declares a one-to-many relationship with table, correlated to the
+// base table by the on condition. A comparison on a field of the related
+// table (e.g. "orders.status='paid'") is emitted as an EXISTS subquery, which
+// filters without multiplying rows the way a JOIN does:
+//
+//	EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.status = ?)
+//
+// Each comparison gets its own subquery, so "orders.status='paid' &&
+// orders.total>100" matches users with a paid order and an order over 100,
+// not necessarily the same one.
+//
+// Example:
+//
+//	qb.HasMany("orders", "orders.user_id = users.id")
+func (qb *QueryBuilder) HasMany(table, on string) *QueryBuilder {
+	qb.relations = append(qb.relations, relation{table: table, on: on})
+	return qb
+}
+
+// relationOf returns the relationship declared for the table a field is
+// qualified with, if any.
+func (qb *QueryBuilder) relationOf(field string) (relation, bool) {
+	table, _, ok := strings.Cut(field, ".")
+	if !ok {
+		return relation{}, false
+	}
+	for _, r := range qb.relations {
+		if r.table == table {
+			return r, true
+		}
+	}
+	return relation{}, false
+}
+
+// SetRawWhere attaches a hand-written predicate that is AND-combined with the
+// parsed filter. Use it for conditions the filter grammar can't express
+// (EXISTS, window functions, ...).
+// Placeholders may be written as "?" or "$1"/":1" (numbered relative to args)
+// and are renumbered to the configured placeholder style.
+//
+// Example:
+//
+//	qb.SetRawWhere("EXISTS (SELECT 1 FROM orders o WHERE o.user_id = users.id AND o.total > $1)", 100)
+func (qb *QueryBuilder) SetRawWhere(sql string, args ...any) *QueryBuilder {
+	qb.rawWhere = &rawWhere{sql: sql, args: args}
+	return qb
+}
+
+// SetInSubquery adds a "field IN (subquery)" predicate that is AND-combined
+// with the parsed filter. The subquery is emitted verbatim and its placeholders
+// are renumbered like SetRawWhere's, so subArgs land at the right position.
+// Calling it again adds another predicate.
+//
+// Example:
+//
+//	qb.SetInSubquery("user_id", "SELECT id FROM admins WHERE org_id = ?", 7)
+func (qb *QueryBuilder) SetInSubquery(field, subSQL string, subArgs ...any) *QueryBuilder {
+	qb.inSubqueries = append(qb.inSubqueries, inSubquery{
+		field: field,
+		sub:   rawWhere{sql: subSQL, args: subArgs},
+	})
+	return qb
+}
+
+// AddInCondition adds a "field IN (values)" predicate built from Go values,
+// AND-combined with the parsed filter. Its args follow the filter's args. With
+// SetArrayParams on Postgres the values are bound as a single array. An empty
+// values slice matches no rows.
+//
+// Example:
+//
+//	qb.AddInCondition("id", []any{1, 2, 3})
+func (qb *QueryBuilder) AddInCondition(field string, values []any) *QueryBuilder {
+	qb.inConditions = append(qb.inConditions, inCondition{field: field, values: values})
+	return qb
+}
+
 // SetFields sets the fields to select.
 func (qb *QueryBuilder) SetFields(fields []string) *QueryBuilder {
 	qb.fields = fields
 	return qb
 }
 
+// SetGroupBy sets the GROUP BY fields, for aggregate queries such as
+// fields "category,COUNT(id) AS total" grouped by "category".
+func (qb *QueryBuilder) SetGroupBy(fields []string) *QueryBuilder {
+	qb.groupBy = fields
+	qb.groupRollup = false
+	return qb
+}
+
+// SetDistinctOn keeps only the first row of each group of rows with equal
+// values of fields, emitting Postgres "SELECT DISTINCT ON (user_id) ...".
+// Which row comes first is decided by the ORDER BY, whose leading fields
+// must match the DISTINCT ON fields. Other dialects are rejected by ToSQL.
+//
+// Example:
+//
+//	qb.SetDistinctOn("user_id")
+//	qb.SetSort([]string{"user_id", "-created_at"}) // latest row per user
+func (qb *QueryBuilder) SetDistinctOn(fields ...string) *QueryBuilder {
+	qb.distinctOn = fields
+	return qb
+}
+
+// SetGroupByRollup sets the GROUP BY fields wrapped in ROLLUP, adding
+// subtotal rows for each prefix of the fields and a grand total, e.g.
+// "GROUP BY ROLLUP(category, status)". MySQL emits "GROUP BY category,
+// status WITH ROLLUP"; SQLite has no ROLLUP and is rejected by ToSQL.
+func (qb *QueryBuilder) SetGroupByRollup(fields []string) *QueryBuilder {
+	qb.groupBy = fields
+	qb.groupRollup = true
+	return qb
+}
+
+// EnsureFields adds fields to the SELECT when the selected fields don't
+// already include them, e.g. the primary key needed for ORM scanning or
+// cursor pagination. They are appended after the selected fields; a query
+// selecting "*" is unchanged. Ensured fields are validated like selected ones.
+//
+// Example:
+//
+//	qb.SetFields([]string{"name"})
+//	qb.EnsureFields("id") // SELECT name, id FROM users
+func (qb *QueryBuilder) EnsureFields(fields ...string) *QueryBuilder {
+	qb.ensuredFields = append(qb.ensuredFields, fields...)
+	return qb
+}
+
 // SetFilter sets the filter expression.
 func (qb *QueryBuilder) SetFilter(filter *parser.Filter) *QueryBuilder {
 	qb.filter = filter
@@ -79,6 +497,47 @@ func (qb *QueryBuilder) SetSort(sort []string) *QueryBuilder {
 	return qb
 }
 
+// SetDefaultSort sets the default sort fields.
+// When the client provides a sort, any default field not already present is
+// appended as a tiebreaker. When the client omits sort, the defaults are used
+// as the whole ORDER BY.
+func (qb *QueryBuilder) SetDefaultSort(fields ...string) *QueryBuilder {
+	qb.defaultSort = fields
+	return qb
+}
+
+// SetSortCollations sets the collation emitted for sort fields, e.g.
+// {"name": `"C"`} for Postgres or {"name": "utf8mb4_unicode_ci"} for MySQL,
+// producing "ORDER BY name COLLATE "C" ASC". Collations are written as given
+// and must not come from user input. Fields without an entry omit COLLATE.
+func (qb *QueryBuilder) SetSortCollations(collations map[string]string) *QueryBuilder {
+	qb.sortCollations = collations
+	return qb
+}
+
+// SetSortOrders declares custom sort orders, e.g.
+// {"status": {"active", "pending"}}, used by a "status:custom" sort to emit
+// "ORDER BY CASE status WHEN 'active' THEN 0 WHEN 'pending' THEN 1 ELSE 2 END ASC".
+// Values not listed sort last; "-status:custom" reverses the order. The values
+// are rendered as literals and must not come from user input.
+func (qb *QueryBuilder) SetSortOrders(orders map[string][]string) *QueryBuilder {
+	qb.sortOrders = orders
+	return qb
+}
+
+// SetCaseFold marks fields compared case-insensitively with = and !=, e.g.
+// for username or email lookups. String values are lowercased and the
+// comparison is emitted as "LOWER(email) = LOWER(?)".
+func (qb *QueryBuilder) SetCaseFold(fields ...string) *QueryBuilder {
+	if qb.caseFold == nil {
+		qb.caseFold = make(map[string]bool, len(fields))
+	}
+	for _, field := range fields {
+		qb.caseFold[field] = true
+	}
+	return qb
+}
+
 // SetLimit sets the limit.
 func (qb *QueryBuilder) SetLimit(limit int) *QueryBuilder {
 	qb.limit = limit
@@ -93,71 +552,811 @@ func (qb *QueryBuilder) SetOffset(offset int) *QueryBuilder {
 
 // ToSQL builds the complete SQL query and returns the SQL string and arguments.
 // This method does not perform validation. Use Validate().ToSQL() for validated queries.
+// Each build returns a new args slice, so args from a previous build stay
+// valid after the QueryBuilder is built again.
 func (qb *QueryBuilder) ToSQL() (string, []any, error) {
-	qb.args = make([]any, 0) // Reset args
-	qb.placeholderCount = 0  // Reset placeholder counter
+	qb.reset()
 
-	var sql strings.Builder
+	sql := getBuffer()
+	defer putBuffer(sql)
+
+	// SELECT and FROM clauses
+	qb.writeSelect(sql)
+
+	// WHERE clause
+	if whereSQL := qb.buildWhere(); whereSQL != "" {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(whereSQL)
+	}
+
+	// GROUP BY clause
+	qb.writeGroupBy(sql)
+
+	// ORDER BY clause
+	orderBy := qb.buildOrderBy()
+	if orderBy != "" {
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(orderBy)
+	}
+
+	// LIMIT and OFFSET clauses
+	qb.writeLimitOffset(sql, orderBy != "")
+
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+
+	query, args := sql.String(), qb.builtArgs()
+	qb.logQuery(query, args)
+	return query, args, nil
+}
+
+// writeGroupBy writes the GROUP BY clause, wrapping the fields in the
+// dialect's ROLLUP syntax when requested.
+func (qb *QueryBuilder) writeGroupBy(sql *bytes.Buffer) {
+	if len(qb.groupBy) == 0 {
+		return
+	}
+
+	columns := make([]string, len(qb.groupBy))
+	for i, field := range qb.groupBy {
+		columns[i] = qb.column(field)
+	}
+	list := strings.Join(columns, ", ")
+
+	sql.WriteString(" GROUP BY ")
+	switch {
+	case !qb.groupRollup:
+		sql.WriteString(list)
+	case qb.dialect == DialectMySQL:
+		sql.WriteString(list + " WITH ROLLUP")
+	case qb.dialect == DialectSQLite:
+		qb.fail(errors.New("GROUP BY ROLLUP is not supported by the sqlite dialect"))
+	default:
+		sql.WriteString("ROLLUP(" + list + ")")
+	}
+}
+
+// writeLimitOffset writes the LIMIT and OFFSET clauses. MySQL rejects OFFSET
+// without LIMIT, so its documented "all rows" sentinel is used there. SQL
+// Server uses OFFSET ... ROWS FETCH NEXT ... ROWS ONLY, which requires an
+// ORDER BY; "ORDER BY (SELECT NULL)" is added when the query has none.
+func (qb *QueryBuilder) writeLimitOffset(sql *bytes.Buffer, ordered bool) {
+	if qb.dialect == DialectSQLServer {
+		if qb.limit <= 0 && qb.offset <= 0 {
+			return
+		}
+		if !ordered {
+			sql.WriteString(" ORDER BY (SELECT NULL)")
+		}
+		sql.WriteString(" OFFSET " + strconv.Itoa(max(qb.offset, 0)) + " ROWS")
+		if qb.limit > 0 {
+			sql.WriteString(" FETCH NEXT " + strconv.Itoa(qb.limit) + " ROWS ONLY")
+		}
+		return
+	}
+
+	if qb.limit > 0 {
+		sql.WriteString(" LIMIT ")
+		sql.WriteString(strconv.Itoa(qb.limit))
+	} else if qb.offset > 0 && qb.dialect == DialectMySQL {
+		sql.WriteString(" LIMIT " + mysqlMaxLimit)
+	}
+
+	if qb.offset > 0 {
+		sql.WriteString(" OFFSET ")
+		sql.WriteString(strconv.Itoa(qb.offset))
+	}
+}
+
+// ToCountSQL builds a query counting the rows matched by the filter, e.g.
+// "SELECT COUNT(*) FROM users WHERE age > ?", for paginated responses that
+// report a total. Fields, sort, limit, offset and the cursor are ignored.
+func (qb *QueryBuilder) ToCountSQL() (string, []any, error) {
+	return qb.buildCount("*")
+}
+
+// ToCountDistinctSQL builds a query counting the distinct values of field
+// among the rows matched by the filter, e.g.
+// "SELECT COUNT(DISTINCT category) FROM products WHERE price > ?".
+func (qb *QueryBuilder) ToCountDistinctSQL(field string) (string, []any, error) {
+	if !identPattern.MatchString(field) {
+		return "", nil, fmt.Errorf("invalid count field '%s'", field)
+	}
+	return qb.buildCount("DISTINCT " + qb.qualify(field))
+}
+
+// buildCount builds a COUNT query over expr with the WHERE clause only.
+// The cursor's keyset predicate selects a page, so it is left out.
+func (qb *QueryBuilder) buildCount(expr string) (string, []any, error) {
+	qb.reset()
+
+	after := qb.after
+	qb.after = nil
+	defer func() { qb.after = after }()
 
+	sql := getBuffer()
+	defer putBuffer(sql)
+
+	sql.WriteString("SELECT COUNT(" + expr + ")")
+	qb.writeFrom(sql)
+
+	if whereSQL := qb.buildWhere(); whereSQL != "" {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(whereSQL)
+	}
+
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+
+	query, args := sql.String(), qb.builtArgs()
+	qb.logQuery(query, args)
+	return query, args, nil
+}
+
+// Arg is a bound argument with the kind of the value it was parsed from.
+// Use it for drivers that need explicit type information.
+type Arg struct {
+	Value any
+	Kind  parser.ValueKind
+}
+
+// ToTypedSQL builds the complete SQL query and returns the SQL string and
+// arguments paired with their kinds.
+func (qb *QueryBuilder) ToTypedSQL() (string, []Arg, error) {
+	query, args, err := qb.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	typed := make([]Arg, len(args))
+	for i, arg := range args {
+		typed[i] = Arg{Value: arg, Kind: qb.kinds[i]}
+	}
+	return query, typed, nil
+}
+
+// DebugArg pairs a bound argument with the placeholder it is bound to.
+type DebugArg struct {
+	Placeholder string
+	Value       any
+}
+
+// String formats the pair for logs, e.g. "$1=18" or "$2='active'".
+func (a DebugArg) String() string {
+	if s, ok := a.Value.(string); ok {
+		return a.Placeholder + "='" + s + "'"
+	}
+	return fmt.Sprintf("%s=%v", a.Placeholder, a.Value)
+}
+
+// ToSQLDebug builds the complete SQL query like ToSQL and pairs each argument
+// with its placeholder, so logs can correlate them.
+//
+// Example:
+//
+//	sql, args, err := qb.ToSQLDebug()
+//	log.Printf("%s %v", sql, args) // ... [$1=18 $2='active']
+func (qb *QueryBuilder) ToSQLDebug() (string, []DebugArg, error) {
+	query, args, err := qb.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+
+	debug := make([]DebugArg, len(args))
+	for i, arg := range args {
+		placeholder := "?"
+		if qb.placeholderStyle != "?" {
+			placeholder = qb.numberedPlaceholder(qb.placeholderStart + i + 1)
+		}
+		debug[i] = DebugArg{Placeholder: placeholder, Value: arg}
+	}
+	return query, debug, nil
+}
+
+// ToSQLInline builds the complete SQL query with every value rendered as a
+// literal instead of a placeholder, e.g. for a "copy as SQL" debug feature.
+// Strings are single-quoted with embedded quotes doubled; numbers and
+// booleans are written raw.
+//
+// The result is for display only. Escaping is best-effort and dialect
+// settings such as backslash handling vary, so never execute it; use ToSQL
+// with bound arguments instead.
+func (qb *QueryBuilder) ToSQLInline() (string, error) {
+	qb.inline = true
+	defer func() { qb.inline = false }()
+
+	query, _, err := qb.ToSQL()
+	return query, err
+}
+
+// ToSQLWithTimeout builds the complete SQL query as ToSQL does, bounded to
+// timeout. RestQL never executes SQL itself, so the bound is either part of
+// the query or a setup statement for the caller to run first:
+//
+//   - Postgres returns the setup "SET LOCAL statement_timeout = '2000ms'",
+//     which must run in the query's transaction and ends with it.
+//   - MySQL adds the optimizer hint "SELECT /*+ MAX_EXECUTION_TIME(2000) */"
+//     to the query and returns no setup, so pooled connections keep no state.
+//
+// Other dialects are rejected. The timeout is rounded up to whole
+// milliseconds; when it is not positive, the query is returned as by ToSQL
+// with no setup.
+//
+// Example:
+//
+//	setup, query, args, err := qb.ToSQLWithTimeout(2 * time.Second)
+//	if setup != "" {
+//	    tx.Exec(setup)
+//	}
+//	rows, err := tx.Query(query, args...)
+func (qb *QueryBuilder) ToSQLWithTimeout(timeout time.Duration) (string, string, []any, error) {
+	if timeout > 0 && qb.dialect != DialectPostgres && qb.dialect != DialectMySQL {
+		return "", "", nil, errors.New("statement timeout requires the postgres or mysql dialect")
+	}
+
+	query, args, err := qb.ToSQL()
+	if err != nil || timeout <= 0 {
+		return "", query, args, err
+	}
+
+	ms := (timeout + time.Millisecond - 1).Milliseconds()
+	if qb.dialect == DialectMySQL {
+		rest, _ := strings.CutPrefix(query, "SELECT ")
+		return "", fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */ ", ms) + rest, args, nil
+	}
+	return fmt.Sprintf("SET LOCAL statement_timeout = '%dms'", ms), query, args, nil
+}
+
+// ToSQLFor builds the complete SQL query as ToSQL does, but rendered for
+// dialect with its native placeholders and identifier quoting (see
+// Dialect.Placeholder and Dialect.QuotesIdentifiers), e.g. to compare the
+// Postgres and MySQL output of one filter while debugging. The builder's own
+// dialect, placeholder and quoting settings are left unchanged.
+func (qb *QueryBuilder) ToSQLFor(dialect Dialect) (string, []any, error) {
+	saved, placeholder, quote := qb.dialect, qb.placeholderStyle, qb.quoteIdentifiers
+	defer func() {
+		qb.dialect, qb.placeholderStyle, qb.quoteIdentifiers = saved, placeholder, quote
+	}()
+
+	qb.dialect = dialect
+	qb.placeholderStyle = dialect.Placeholder()
+	qb.quoteIdentifiers = dialect.QuotesIdentifiers()
+	return qb.ToSQL()
+}
+
+// reset clears the state of a previous build. Args returned by a previous
+// build stay owned by the caller, so each build gets a new slice, sized from
+// the previous one so repeated builds (e.g. count + data queries) don't grow it.
+func (qb *QueryBuilder) reset() {
+	qb.args = make([]any, 0, cap(qb.args))
+	qb.kinds = qb.kinds[:0]
+	qb.placeholderCount = qb.placeholderStart
+	qb.err = nil
+}
+
+// builtArgs returns the args of the current build, capped so that appends by
+// the caller never write into spare capacity of the builder's slice.
+func (qb *QueryBuilder) builtArgs() []any {
+	return qb.args[:len(qb.args):len(qb.args)]
+}
+
+// Select builds only the SELECT and FROM clauses, e.g. "SELECT id, name FROM users".
+// Use it to compose the projection with hand-written WHERE/ORDER BY fragments.
+// It reports the same errors as ToSQL for the SELECT and FROM clauses, such as
+// an invalid table name or aggregate.
+func (qb *QueryBuilder) Select() (string, error) {
+	qb.reset()
+
+	sql := getBuffer()
+	defer putBuffer(sql)
+
+	qb.writeSelect(sql)
+	if qb.err != nil {
+		return "", qb.err
+	}
+	return sql.String(), nil
+}
+
+// writeSelect writes the SELECT and FROM clauses.
+func (qb *QueryBuilder) writeSelect(sql *bytes.Buffer) {
 	// SELECT clause
 	sql.WriteString("SELECT ")
-	if len(qb.fields) > 0 {
-		sql.WriteString(strings.Join(qb.fields, ", "))
+	qb.writeDistinctOn(sql)
+	if fields := qb.selectFields(); len(fields) > 0 {
+		for i, field := range fields {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString(qb.selectSQL(field))
+		}
 	} else {
 		sql.WriteString("*")
 	}
 
+	qb.writeFrom(sql)
+}
+
+// writeDistinctOn writes the DISTINCT ON clause, which only Postgres
+// supports.
+func (qb *QueryBuilder) writeDistinctOn(sql *bytes.Buffer) {
+	if len(qb.distinctOn) == 0 {
+		return
+	}
+	if qb.dialect != DialectPostgres {
+		qb.fail(errors.New("DISTINCT ON requires the postgres dialect; use GROUP BY to deduplicate on other dialects"))
+		return
+	}
+
+	columns := make([]string, len(qb.distinctOn))
+	for i, field := range qb.distinctOn {
+		if !identPattern.MatchString(field) {
+			qb.fail(fmt.Errorf("invalid DISTINCT ON field '%s'", field))
+			return
+		}
+		columns[i] = qb.column(field)
+	}
+	sql.WriteString("DISTINCT ON (" + strings.Join(columns, ", ") + ") ")
+}
+
+// selectFields returns the selected fields followed by the ensured fields
+// they don't include, or nil to select "*".
+func (qb *QueryBuilder) selectFields() []string {
+	if len(qb.fields) == 0 || len(qb.ensuredFields) == 0 {
+		return qb.fields
+	}
+	fields := slices.Clone(qb.fields)
+	for _, field := range qb.ensuredFields {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// writeFrom writes the FROM and JOIN clauses.
+func (qb *QueryBuilder) writeFrom(sql *bytes.Buffer) {
 	// FROM clause
+	switch {
+	case qb.table == "":
+		qb.fail(errors.New("table name is empty"))
+	case !identPattern.MatchString(qb.table):
+		qb.fail(fmt.Errorf("invalid table name '%s'", qb.table))
+	}
 	sql.WriteString(" FROM ")
-	sql.WriteString(qb.table)
+	sql.WriteString(qb.ident(qb.table))
+	if qb.tableAlias != "" {
+		sql.WriteString(" AS ")
+		sql.WriteString(qb.ident(qb.tableAlias))
+	}
 
-	// WHERE clause
-	if qb.filter != nil && qb.filter.Expression != nil {
-		whereSQL := qb.buildOrExpr(qb.filter.Expression)
-		if whereSQL != "" {
-			sql.WriteString(" WHERE ")
-			sql.WriteString(whereSQL)
-		}
+	// JOIN clauses
+	for _, j := range qb.joins {
+		sql.WriteString(" INNER JOIN ")
+		sql.WriteString(j.table)
+		sql.WriteString(" ON ")
+		sql.WriteString(j.on)
 	}
+}
 
-	// ORDER BY clause
-	if len(qb.sort) > 0 {
-		sql.WriteString(" ORDER BY ")
-		orderClauses := make([]string, 0, len(qb.sort))
-		for _, s := range qb.sort {
-			if strings.HasPrefix(s, "-") {
-				orderClauses = append(orderClauses, s[1:]+" DESC")
-			} else {
-				orderClauses = append(orderClauses, s+" ASC")
+// buildOrderBy builds the ORDER BY expressions, without the keyword.
+func (qb *QueryBuilder) buildOrderBy() string {
+	var sql strings.Builder
+	for _, s := range qb.orderBy() {
+		expr, err := parseSort(s)
+		if err != nil {
+			qb.fail(err)
+			continue
+		}
+		if n := len(qb.selectFields()); expr.position > n {
+			qb.fail(fmt.Errorf("sort position %d is out of range: %d fields selected", expr.position, n))
+			continue
+		}
+		collation := ""
+		if expr.function == "" && expr.position == 0 && !expr.custom {
+			collation = qb.sortCollations[expr.fields[0]]
+		}
+		var order []string
+		if expr.custom {
+			var ok bool
+			if order, ok = qb.sortOrders[expr.fields[0]]; !ok {
+				qb.fail(fmt.Errorf("field '%s' has no custom sort order", expr.fields[0]))
+				continue
 			}
 		}
-		sql.WriteString(strings.Join(orderClauses, ", "))
+		if sql.Len() > 0 {
+			sql.WriteString(", ")
+		}
+		for j, field := range expr.fields {
+			expr.fields[j] = qb.column(field)
+		}
+		if expr.custom {
+			qb.writeCustomOrder(&sql, expr.fields[0], order)
+		} else {
+			sql.WriteString(expr.expr())
+		}
+		if collation != "" {
+			sql.WriteString(" COLLATE ")
+			sql.WriteString(collation)
+		}
+		if expr.desc {
+			sql.WriteString(" DESC")
+		} else {
+			sql.WriteString(" ASC")
+		}
 	}
+	return sql.String()
+}
 
-	// LIMIT clause
-	if qb.limit > 0 {
-		sql.WriteString(fmt.Sprintf(" LIMIT %d", qb.limit))
+// writeCustomOrder writes a CASE expression ranking column by its position in
+// order, with unlisted values ranked last.
+func (qb *QueryBuilder) writeCustomOrder(sql *strings.Builder, column string, order []string) {
+	sql.WriteString("CASE ")
+	sql.WriteString(column)
+	for i, value := range order {
+		sql.WriteString(" WHEN ")
+		sql.WriteString(qb.dialect.literal(value))
+		sql.WriteString(" THEN ")
+		sql.WriteString(strconv.Itoa(i))
 	}
+	sql.WriteString(" ELSE ")
+	sql.WriteString(strconv.Itoa(len(order)))
+	sql.WriteString(" END")
+}
 
-	// OFFSET clause
-	if qb.offset > 0 {
-		sql.WriteString(fmt.Sprintf(" OFFSET %d", qb.offset))
+// orderBy returns the effective sort fields, appending default sort fields
+// that the client sort does not already include.
+func (qb *QueryBuilder) orderBy() []string {
+	if len(qb.defaultSort) == 0 {
+		return qb.sort
+	}
+
+	seen := make(map[string]bool, len(qb.sort))
+	sort := make([]string, 0, len(qb.sort)+len(qb.defaultSort))
+	for _, s := range qb.sort {
+		seen[sortKey(s)] = true
+		sort = append(sort, s)
+	}
+	for _, s := range qb.defaultSort {
+		key := sortKey(s)
+		if !seen[key] {
+			seen[key] = true
+			sort = append(sort, s)
+		}
+	}
+	return sort
+}
+
+// sortKey returns the sort entry without its direction, used to detect
+// entries that sort by the same expression.
+func sortKey(s string) string {
+	if expr, err := parseSort(s); err == nil {
+		return expr.expr()
+	}
+	return s
+}
+
+// ToNamedSQL builds the complete SQL query and returns the SQL string and
+// named arguments (p0, p1, ...) suitable for database/sql.
+// The placeholder style must be ":p0".
+func (qb *QueryBuilder) ToNamedSQL() (string, []sql.NamedArg, error) {
+	if qb.placeholderStyle != namedPlaceholder {
+		return "", nil, fmt.Errorf("named arguments require placeholder style '%s', got '%s'", namedPlaceholder, qb.placeholderStyle)
+	}
+
+	query, args, err := qb.ToSQL()
+	if err != nil {
+		return "", nil, err
 	}
+	return query, namedArgs(args, qb.placeholderStart), nil
+}
 
-	return sql.String(), qb.args, nil
+// namedArgs converts ordered arguments into named arguments p<start>, p<start+1>, ...
+func namedArgs(args []any, start int) []sql.NamedArg {
+	named := make([]sql.NamedArg, 0, len(args))
+	for i, arg := range args {
+		named = append(named, sql.Named(fmt.Sprintf("p%d", start+i), arg))
+	}
+	return named
 }
 
 // Where builds only the WHERE clause.
-func (qb *QueryBuilder) Where() (string, []any) {
-	qb.args = make([]any, 0) // Reset args
-	qb.placeholderCount = 0  // Reset placeholder counter
+// Like ToSQL, each build returns a new args slice.
+// The returned ok is false when there is no predicate (no filter, or every
+// condition was pruned), so callers can omit the WHERE keyword.
+// Build errors (e.g. dialect-specific operators) are only reported by ToSQL.
+//
+// Example:
+//
+//	if clause, args, ok := qb.Where(); ok {
+//	    query += " WHERE " + clause
+//	}
+func (qb *QueryBuilder) Where() (string, []any, bool) {
+	qb.reset()
+
+	whereSQL := qb.buildWhere()
+	if whereSQL == "" {
+		return "", nil, false
+	}
+	return whereSQL, qb.builtArgs(), true
+}
 
-	if qb.filter == nil || qb.filter.Expression == nil {
+// WhereAppend builds the WHERE predicate as "AND (<conditions>)" for appending
+// to a query that already has a WHERE clause. It returns "" and nil args when
+// there is no predicate. Combine it with SetPlaceholderStart when the existing
+// query binds numbered placeholders.
+//
+// Example:
+//
+//	clause, args := qb.WhereAppend()
+//	query := "SELECT * FROM users WHERE tenant_id = 7 " + clause
+func (qb *QueryBuilder) WhereAppend() (string, []any) {
+	whereSQL, args, ok := qb.Where()
+	if !ok {
 		return "", nil
 	}
+	return "AND (" + trimOuterParens(whereSQL) + ")", args
+}
+
+// OrderBy builds only the ORDER BY expressions, without the keyword
+// (e.g. "created_at DESC, id ASC"), for query builders that take the sort
+// separately. It returns "" when there is no sort. Invalid sort fields are
+// skipped; they are only reported by ToSQL. Sort fields are not checked
+// against any allowlist; use Validator.OrderBy for client input.
+//
+// Example (GORM):
+//
+//	v := qb.Validate(WithAllowedFields(allowed))
+//	clause, args, _, err := v.Where()
+//	orderBy, err := v.OrderBy()
+//	db.Where(clause, args...).Order(orderBy).Limit(v.Limit()).Offset(v.Offset()).Find(&users)
+func (qb *QueryBuilder) OrderBy() string {
+	return qb.buildOrderBy()
+}
+
+// Limit returns the requested limit, or 0 when none was set.
+func (qb *QueryBuilder) Limit() int {
+	return max(qb.limit, 0)
+}
+
+// Offset returns the requested offset, or 0 when none was set.
+func (qb *QueryBuilder) Offset() int {
+	return max(qb.offset, 0)
+}
+
+// buildRawWhere renumbers the placeholders of a raw predicate or subquery to the
+// configured style and appends its args in placeholder order.
+// Both "?" (sequential) and "$N"/":N" (1-based, relative to the raw args)
+// placeholders are recognized; single-quoted literals are left untouched.
+// name identifies the fragment in errors.
+func (qb *QueryBuilder) buildRawWhere(rw *rawWhere, name string) string {
+	raw := rw.sql
+	var sql strings.Builder
+	next := 0
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+
+		// Copy string literals verbatim
+		if c == '\'' {
+			end := strings.IndexByte(raw[i+1:], '\'')
+			if end < 0 {
+				sql.WriteString(raw[i:])
+				break
+			}
+			sql.WriteString(raw[i : i+end+2])
+			i += end + 1
+			continue
+		}
+
+		index := -1
+		switch {
+		case c == '?':
+			index = next
+			next++
+		case (c == '$' || c == ':') && i+1 < len(raw) && isDigit(raw[i+1]):
+			j := i + 1
+			for j < len(raw) && isDigit(raw[j]) {
+				j++
+			}
+			n, _ := strconv.Atoi(raw[i+1 : j])
+			index = n - 1
+			i = j - 1
+		default:
+			sql.WriteByte(c)
+			continue
+		}
+
+		if index < 0 || index >= len(rw.args) {
+			qb.fail(fmt.Errorf("%s placeholder %d has no matching argument (%d given)", name, index+1, len(rw.args)))
+			return ""
+		}
+		arg := rw.args[index]
+		qb.addArg(arg, kindOf(arg))
+		sql.WriteString(qb.getPlaceholder())
+	}
 
-	whereSQL := qb.buildOrExpr(qb.filter.Expression)
-	return whereSQL, qb.args
+	return sql.String()
+}
+
+// ArgCount returns the number of arguments ToSQL binds, without building SQL
+// or touching the args of a previous build. Use it for pre-flight checks
+// against driver limits (e.g. Postgres' 65535 parameters).
+func (qb *QueryBuilder) ArgCount() int {
+	count := 0
+	if qb.filter != nil {
+		count += qb.countOrExpr(qb.filter.Expression)
+	}
+	for _, in := range qb.inSubqueries {
+		count += countRawPlaceholders(in.sub.sql)
+	}
+	for _, in := range qb.inConditions {
+		switch {
+		case len(in.values) == 0:
+		case qb.arrayParams && qb.dialect == DialectPostgres:
+			count++
+		default:
+			count += len(in.values)
+		}
+	}
+	count += qb.countKeyset()
+	if qb.rawWhere != nil {
+		count += countRawPlaceholders(qb.rawWhere.sql)
+	}
+	return count
+}
+
+// countOrExpr counts the arguments bound for an OR expression.
+func (qb *QueryBuilder) countOrExpr(expr *parser.OrExpr) int {
+	if expr == nil {
+		return 0
+	}
+	and := expr.And
+	if qb.foldOrEquals {
+		and = foldOrEquals(and, qb.caseFold)
+	}
+
+	count := 0
+	for _, andExpr := range and {
+		if andExpr == nil {
+			continue
+		}
+		for _, comp := range andExpr.Comparison {
+			count += qb.countComparison(comp)
+		}
+	}
+	return count
+}
+
+// countComparison counts the arguments bound for a comparison, mirroring
+// buildComparison.
+func (qb *QueryBuilder) countComparison(comp *parser.Comparison) int {
+	switch {
+	case comp == nil || comp.Left == nil:
+		return 0
+	case comp.Left.SubExpr != nil:
+		return qb.countOrExpr(comp.Left.SubExpr)
+	case comp.Left.Field == "" || comp.Null != nil:
+		return 0
+	case comp.Op == nil && comp.Right == nil:
+		if qb.barePredicates {
+			return 1
+		}
+		return 0
+	case comp.Op == nil || comp.Right == nil:
+		return 0
+	case comp.To != nil:
+		return 2
+	case (comp.Op.In || comp.Op.NotIn) && comp.Right.Array != nil:
+		values, _ := splitNulls(comp.Right.Array.Values)
+		n := len(values)
+		if n > 0 && qb.arrayParams && qb.dialect == DialectPostgres {
+			return 1
+		}
+		return n
+	default:
+		return 1
+	}
+}
+
+// countRawPlaceholders counts the placeholders of a hand-written fragment,
+// skipping string literals like buildRawWhere.
+func countRawPlaceholders(raw string) int {
+	count := 0
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '\'':
+			end := strings.IndexByte(raw[i+1:], '\'')
+			if end < 0 {
+				return count
+			}
+			i += end + 1
+		case c == '?':
+			count++
+		case (c == '$' || c == ':') && i+1 < len(raw) && isDigit(raw[i+1]):
+			count++
+			for i+1 < len(raw) && isDigit(raw[i+1]) {
+				i++
+			}
+		}
+	}
+	return count
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// buildWhere builds the top-level WHERE expression.
+// The parsed filter, IN-subqueries, IN-conditions and the raw predicate are
+// AND-combined.
+func (qb *QueryBuilder) buildWhere() string {
+	parts := make([]string, 0, 2+len(qb.inSubqueries)+len(qb.inConditions))
+	if err := qb.filter.Err(); err != nil {
+		qb.fail(err)
+	}
+	if qb.filter != nil && qb.filter.Expression != nil {
+		if sql := qb.buildOrExpr(qb.filter.Expression); sql != "" {
+			parts = append(parts, sql)
+		}
+	}
+	for i := range qb.inSubqueries {
+		in := &qb.inSubqueries[i]
+		if sql := qb.buildRawWhere(&in.sub, "subquery"); sql != "" {
+			parts = append(parts, qb.qualify(in.field)+" IN ("+sql+")")
+		}
+	}
+	for _, in := range qb.inConditions {
+		parts = append(parts, qb.buildInCondition(qb.qualify(in.field), in.values))
+	}
+	if len(qb.after) > 0 {
+		if sql := qb.buildKeyset(); sql != "" {
+			parts = append(parts, sql)
+		}
+	}
+	if qb.rawWhere != nil {
+		if sql := qb.buildRawWhere(qb.rawWhere, "raw where"); sql != "" {
+			parts = append(parts, "("+sql+")")
+		}
+	}
+
+	sql := ""
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		sql = parts[0]
+	default:
+		sql = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	if qb.minimalParens {
+		return trimOuterParens(sql)
+	}
+	return sql
+}
+
+// trimOuterParens removes parentheses that enclose the whole expression.
+func trimOuterParens(sql string) string {
+	for len(sql) >= 2 && sql[0] == '(' && sql[len(sql)-1] == ')' {
+		depth := 0
+		for i := 0; i < len(sql)-1; i++ {
+			switch sql[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			// The opening parenthesis closes before the end
+			if depth == 0 {
+				return sql
+			}
+		}
+		sql = sql[1 : len(sql)-1]
+	}
+	return sql
 }
 
 // buildOrExpr builds SQL for OR expressions.
@@ -166,9 +1365,21 @@ func (qb *QueryBuilder) buildOrExpr(expr *parser.OrExpr) string {
 		return ""
 	}
 
-	parts := make([]string, 0, len(expr.And))
-	for _, andExpr := range expr.And {
-		if sql := qb.buildAndExpr(andExpr); sql != "" {
+	and := expr.And
+	if qb.foldOrEquals {
+		and = foldOrEquals(and, qb.caseFold)
+	}
+
+	// A level mixing AND and OR keeps the client's parentheses, even around
+	// single comparisons, so the emitted grouping reads as written
+	mixed := false
+	for _, andExpr := range and {
+		mixed = mixed || len(and) > 1 && andExpr != nil && len(andExpr.Comparison) > 1
+	}
+
+	parts := make([]string, 0, len(and))
+	for _, andExpr := range and {
+		if sql := qb.buildAndExpr(andExpr, mixed); sql != "" {
 			parts = append(parts, sql)
 		}
 	}
@@ -183,17 +1394,24 @@ func (qb *QueryBuilder) buildOrExpr(expr *parser.OrExpr) string {
 	return "(" + strings.Join(parts, " OR ") + ")"
 }
 
-// buildAndExpr builds SQL for AND expressions.
-func (qb *QueryBuilder) buildAndExpr(expr *parser.AndExpr) string {
+// buildAndExpr builds SQL for AND expressions. When keepParens is set,
+// parenthesized subexpressions are emitted in parentheses even when they hold
+// a single comparison.
+func (qb *QueryBuilder) buildAndExpr(expr *parser.AndExpr, keepParens bool) string {
 	if expr == nil {
 		return ""
 	}
 
 	parts := make([]string, 0, len(expr.Comparison))
 	for _, comp := range expr.Comparison {
-		if sql := qb.buildComparison(comp); sql != "" {
-			parts = append(parts, sql)
+		sql := qb.buildComparison(comp)
+		if sql == "" {
+			continue
+		}
+		if keepParens && comp.Left != nil && comp.Left.SubExpr != nil && trimOuterParens(sql) == sql {
+			sql = "(" + sql + ")"
 		}
+		parts = append(parts, sql)
 	}
 
 	if len(parts) == 0 {
@@ -214,7 +1432,25 @@ func (qb *QueryBuilder) buildComparison(comp *parser.Comparison) string {
 
 	// Handle subexpression in parentheses
 	if comp.Left != nil && comp.Left.SubExpr != nil {
-		return qb.buildOrExpr(comp.Left.SubExpr)
+		sql := qb.buildOrExpr(comp.Left.SubExpr)
+		if comp.Not && sql != "" {
+			if !strings.HasPrefix(sql, "(") {
+				sql = "(" + sql + ")"
+			}
+			return "NOT " + sql
+		}
+		return sql
+	}
+
+	// Negated comparisons (!age>18) are wrapped in NOT; bare predicates
+	// bind the negation as false instead
+	if comp.Not && !comp.Bare() {
+		positive := *comp
+		positive.Not = false
+		if sql := qb.buildComparison(&positive); sql != "" {
+			return "NOT (" + sql + ")"
+		}
+		return ""
 	}
 
 	// Get field name
@@ -227,14 +1463,56 @@ func (qb *QueryBuilder) buildComparison(comp *parser.Comparison) string {
 		return ""
 	}
 
-	// Handle IS NULL / IS NOT NULL
-	if comp.Null != nil {
-		if comp.Null.IsNull {
-			return field + " IS NULL"
+	// Fields of a related table are filtered through EXISTS
+	if rel, ok := qb.relationOf(field); ok {
+		if sql := qb.buildPredicate(comp, field); sql != "" {
+			return "EXISTS (SELECT 1 FROM " + rel.table + " WHERE " + rel.on + " AND " + sql + ")"
 		}
-		if comp.Null.IsNotNull {
-			return field + " IS NOT NULL"
+		return ""
+	}
+
+	return qb.buildPredicate(comp, field)
+}
+
+// buildPredicate builds SQL for a comparison on field.
+func (qb *QueryBuilder) buildPredicate(comp *parser.Comparison, field string) string {
+	field = qb.column(field)
+
+	// "~" and "!~" stand for LIKE, or ILIKE when configured
+	if op := comp.Op.ResolveTilde(qb.tildeILike); op != comp.Op {
+		resolved := *comp
+		resolved.Op = op
+		comp = &resolved
+	}
+
+	// Append arithmetic on the left side verbatim (e.g. "price * quantity")
+	for _, a := range comp.Left.Arith {
+		if a == nil {
+			continue
+		}
+		operand := a.Operand()
+		if a.Field != "" {
+			operand = qb.qualify(operand)
+		}
+		field += " " + a.Op + " " + operand
+	}
+
+	// Handle IS NULL / IS NOT NULL and the null literal (= null, != null)
+	if comp.Null != nil {
+		return qb.buildNullCheck(field, comp.Op, comp.Null)
+	}
+
+	// Handle bare boolean predicates (active, !active)
+	if comp.Bare() {
+		if !qb.barePredicates {
+			// Dropping a negation would match the opposite rows
+			if comp.Not {
+				qb.fail(fmt.Errorf("negated field '%s' requires bare predicates to be enabled", comp.Left.Field))
+			}
+			return ""
 		}
+		qb.addArg(!comp.Not, parser.KindBool)
+		return field + " = " + qb.getPlaceholder()
 	}
 
 	// Handle regular operators
@@ -244,49 +1522,350 @@ func (qb *QueryBuilder) buildComparison(comp *parser.Comparison) string {
 
 	operator := comp.Op.String()
 
+	// Handle CONTAINS on JSONB columns
+	if comp.Op.Contains {
+		return qb.buildContains(field, comp.Right)
+	}
+
 	// Handle IN/NOT IN with arrays
 	if (comp.Op.In || comp.Op.NotIn) && comp.Right.Array != nil {
-		placeholders := make([]string, 0, len(comp.Right.Array.Values))
-		for _, val := range comp.Right.Array.Values {
-			qb.args = append(qb.args, qb.extractValue(val))
-			placeholders = append(placeholders, qb.getPlaceholder())
-		}
-		return field + " " + operator + " (" + strings.Join(placeholders, ", ") + ")"
+		return qb.buildIn(comp, field)
+	}
+
+	// Handle range literals (age=18..65)
+	if comp.To != nil {
+		return qb.buildRange(comp, field)
 	}
 
 	// Handle regular comparison
-	value := qb.extractValue(comp.Right)
-	qb.args = append(qb.args, value)
+	value, kind := qb.coerce(comp.Left.Field, comp.Right)
+
+	// Case-folded fields compare lowercased strings
+	if s, ok := value.(string); ok && (comp.Op.Equal || comp.Op.NotEqual) && qb.caseFold[comp.Left.Field] {
+		qb.addArg(strings.ToLower(s), kind)
+		return "LOWER(" + field + ") " + operator + " LOWER(" + qb.getPlaceholder() + ")"
+	}
+
+	qb.addArg(value, kind)
+
+	// Regular expressions are dialect-specific
+	if comp.Op.Regexp || comp.Op.NotRegexp {
+		return qb.buildRegexp(field, comp.Op.NotRegexp)
+	}
+
+	// NULL-safe comparisons are dialect-specific
+	if comp.Op.NullSafeEqual || comp.Op.NotDistinctFrom {
+		return qb.buildNullSafe(field, false)
+	}
+	if comp.Op.DistinctFrom {
+		return qb.buildNullSafe(field, true)
+	}
+
+	// ILIKE is Postgres-only; other dialects compare lowercased values
+	if (comp.Op.ILike || comp.Op.NotILike) && qb.dialect != DialectPostgres {
+		operator = "LIKE"
+		if comp.Op.NotILike {
+			operator = "NOT LIKE"
+		}
+		return "LOWER(" + field + ") " + operator + " LOWER(" + qb.getPlaceholder() + ")"
+	}
 
 	return field + " " + operator + " " + qb.getPlaceholder()
 }
 
-// extractValue extracts the actual value from a Value node.
-func (qb *QueryBuilder) extractValue(val *parser.Value) any {
-	if val == nil {
-		return nil
+// buildRange builds SQL for a range literal: "=" becomes BETWEEN and "!="
+// NOT BETWEEN, with both bounds inclusive.
+func (qb *QueryBuilder) buildRange(comp *parser.Comparison, field string) string {
+	operator := "BETWEEN"
+	switch {
+	case comp.Op.Equal:
+	case comp.Op.NotEqual:
+		operator = "NOT BETWEEN"
+	default:
+		qb.fail(fmt.Errorf("operator %s on field '%s' cannot compare with a range", comp.Op.String(), field))
+		return ""
+	}
+	if comp.Right.Array != nil || comp.To.Array != nil || comp.Right.Null || comp.To.Null {
+		qb.fail(fmt.Errorf("invalid range bounds for field '%s'", field))
+		return ""
+	}
+
+	qb.addArg(qb.coerce(comp.Left.Field, comp.Right))
+	low := qb.getPlaceholder()
+	qb.addArg(qb.coerce(comp.Left.Field, comp.To))
+	return field + " " + operator + " " + low + " AND " + qb.getPlaceholder()
+}
+
+// buildNullCheck builds SQL for NULL checks; see isNotNull.
+func (qb *QueryBuilder) buildNullCheck(field string, op *parser.Operator, null *parser.NullCheck) string {
+	notNull, err := isNotNull(field, op, null)
+	if err != nil {
+		qb.fail(err)
+		return ""
+	}
+
+	if notNull {
+		return field + " IS NOT NULL"
+	}
+	return field + " IS NULL"
+}
+
+// isNotNull reports whether a NULL check on field tests for NOT NULL.
+// "= null" maps to IS NULL and "!= null" / "<> null" to IS NOT NULL; other
+// operators cannot compare with NULL.
+func isNotNull(field string, op *parser.Operator, null *parser.NullCheck) (bool, error) {
+	notNull := null.IsNotNull
+	if op != nil {
+		switch {
+		case op.Is, op.Equal, op.NullSafeEqual, op.NotDistinctFrom:
+		case op.NotEqual, op.DistinctFrom:
+			notNull = !notNull
+		default:
+			return false, fmt.Errorf("operator %s on field '%s' cannot compare with NULL", op.String(), field)
+		}
+	}
+	return notNull, nil
+}
+
+// buildContains builds SQL for CONTAINS as a Postgres JSONB containment check.
+// The value is JSON-encoded as an array: a scalar becomes a single-element array.
+func (qb *QueryBuilder) buildContains(field string, val *parser.Value) string {
+	if qb.dialect != DialectPostgres {
+		qb.fail(fmt.Errorf("operator CONTAINS on field '%s' requires the postgres dialect", field))
+		return ""
+	}
+
+	values := make([]any, 0)
+	if val.Array != nil {
+		for _, v := range val.Array.Values {
+			value, _ := qb.extractValue(v)
+			values = append(values, value)
+		}
+	} else {
+		value, _ := qb.extractValue(val)
+		values = append(values, value)
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		qb.fail(fmt.Errorf("failed to encode CONTAINS value for field '%s': %w", field, err))
+		return ""
+	}
+
+	qb.addArg(string(encoded), parser.KindString)
+	return field + " @> " + qb.getPlaceholder()
+}
+
+// buildRegexp builds SQL for REGEXP / NOT REGEXP using the dialect's syntax.
+// The pattern has already been appended to args.
+func (qb *QueryBuilder) buildRegexp(field string, negate bool) string {
+	placeholder := qb.getPlaceholder()
+
+	switch qb.dialect {
+	case DialectPostgres:
+		if negate {
+			return field + " !~ " + placeholder
+		}
+		return field + " ~ " + placeholder
+	case DialectOracle:
+		if negate {
+			return "NOT REGEXP_LIKE(" + field + ", " + placeholder + ")"
+		}
+		return "REGEXP_LIKE(" + field + ", " + placeholder + ")"
+	default:
+		if negate {
+			return field + " NOT REGEXP " + placeholder
+		}
+		return field + " REGEXP " + placeholder
+	}
+}
+
+// buildInCondition builds SQL for an IN predicate over Go values.
+func (qb *QueryBuilder) buildInCondition(field string, values []any) string {
+	if len(values) == 0 {
+		return "1 = 0"
+	}
+	if qb.arrayParams && qb.dialect == DialectPostgres {
+		qb.addArg(values, parser.KindArray)
+		return field + " = ANY(" + qb.getPlaceholder() + ")"
+	}
+
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		qb.addArg(value, kindOf(value))
+		placeholders = append(placeholders, qb.getPlaceholder())
 	}
+	return field + " IN (" + strings.Join(placeholders, ", ") + ")"
+}
+
+// buildIn builds SQL for IN / NOT IN with a value list. A NULL element never
+// matches, so it is rewritten as "(field IN (...) OR field IS NULL)", or
+// "(field NOT IN (...) AND field IS NOT NULL)" for NOT IN.
+func (qb *QueryBuilder) buildIn(comp *parser.Comparison, field string) string {
+	negate := comp.Op.NotIn
+	values, hasNull := splitNulls(comp.Right.Array.Values)
 
-	if val.String != nil {
-		// Remove quotes from string
-		s := *val.String
-		if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') {
-			return s[1 : len(s)-1]
+	in := ""
+	switch {
+	case len(values) == 0:
+	case qb.arrayParams && qb.dialect == DialectPostgres:
+		in = qb.buildArrayParam(field, negate, values)
+	default:
+		placeholders := make([]string, 0, len(values))
+		for _, val := range values {
+			qb.addArg(qb.coerce(comp.Left.Field, val))
+			placeholders = append(placeholders, qb.getPlaceholder())
 		}
-		return s
+		in = field + " " + comp.Op.String() + " (" + strings.Join(placeholders, ", ") + ")"
 	}
 
-	if val.Int != nil {
-		return *val.Int
+	switch {
+	case !hasNull && in == "":
+		// An empty list matches nothing for IN and everything for NOT IN
+		if negate {
+			return "1 = 1"
+		}
+		return "1 = 0"
+	case !hasNull && negate && qb.nullSafeNotIn:
+		return "(" + in + " OR " + field + " IS NULL)"
+	case !hasNull:
+		return in
+	case in == "" && negate:
+		return field + " IS NOT NULL"
+	case in == "":
+		return field + " IS NULL"
+	case negate:
+		return "(" + in + " AND " + field + " IS NOT NULL)"
+	default:
+		return "(" + in + " OR " + field + " IS NULL)"
 	}
+}
 
-	if val.Number != nil {
-		return *val.Number
+// splitNulls returns the non-null values of a list and whether it had a
+// null element. A nil element counts as null.
+func splitNulls(values []*parser.Value) ([]*parser.Value, bool) {
+	nonNull := make([]*parser.Value, 0, len(values))
+	for _, val := range values {
+		if val == nil || val.Null {
+			continue
+		}
+		nonNull = append(nonNull, val)
 	}
+	return nonNull, len(nonNull) < len(values)
+}
 
-	if val.Boolean != nil {
-		return val.Boolean.Value()
+// buildArrayParam builds SQL for IN / NOT IN as Postgres ANY / ALL with the
+// values bound as a single array argument.
+func (qb *QueryBuilder) buildArrayParam(field string, negate bool, vals []*parser.Value) string {
+	values := make([]any, 0, len(vals))
+	for _, val := range vals {
+		value, _ := qb.extractValue(val)
+		values = append(values, value)
 	}
+	qb.addArg(values, parser.KindArray)
+
+	if negate {
+		return field + " != ALL(" + qb.getPlaceholder() + ")"
+	}
+	return field + " = ANY(" + qb.getPlaceholder() + ")"
+}
+
+// buildNullSafe builds SQL for NULL-safe comparisons, which treat two NULLs
+// as equal: <=> and IS NOT DISTINCT FROM, or IS DISTINCT FROM when distinct.
+// The value has already been appended to args.
+func (qb *QueryBuilder) buildNullSafe(field string, distinct bool) string {
+	placeholder := qb.getPlaceholder()
 
-	return nil
+	switch qb.dialect {
+	case DialectMySQL:
+		if distinct {
+			return "NOT (" + field + " <=> " + placeholder + ")"
+		}
+		return field + " <=> " + placeholder
+	case DialectSQLite:
+		if distinct {
+			return field + " IS NOT " + placeholder
+		}
+		return field + " IS " + placeholder
+	default:
+		if distinct {
+			return field + " IS DISTINCT FROM " + placeholder
+		}
+		return field + " IS NOT DISTINCT FROM " + placeholder
+	}
+}
+
+// fail records the first error encountered while building.
+func (qb *QueryBuilder) fail(err error) {
+	if qb.err == nil {
+		qb.err = err
+	}
+}
+
+// extractValue extracts the actual value and its kind from a Value node.
+func (qb *QueryBuilder) extractValue(val *parser.Value) (any, parser.ValueKind) {
+	if val == nil {
+		return nil, parser.KindInvalid
+	}
+
+	if val.Date != nil && qb.dateStrings {
+		return val.Date.String(), parser.KindDate
+	}
+
+	return val.Resolve()
+}
+
+// coerce extracts a value compared with field, converting a quoted number to
+// the field's declared numeric type. Values that don't parse are left as is.
+func (qb *QueryBuilder) coerce(field string, val *parser.Value) (any, parser.ValueKind) {
+	value, kind := qb.extractValue(val)
+	s, ok := value.(string)
+	if !ok || kind != parser.KindString {
+		return value, kind
+	}
+
+	switch qb.fieldTypes[field] {
+	case parser.KindInt:
+		if n, err := strconv.Atoi(s); err == nil {
+			return n, parser.KindInt
+		}
+	case parser.KindFloat:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, parser.KindFloat
+		}
+	}
+	return value, kind
+}
+
+// addArg appends a bound argument and its kind.
+// Booleans are bound as 1/0 for dialects that store them as integers.
+func (qb *QueryBuilder) addArg(value any, kind parser.ValueKind) {
+	if b, ok := value.(bool); ok && qb.dialect.intBooleans() {
+		value = 0
+		if b {
+			value = 1
+		}
+	}
+	qb.args = append(qb.args, value)
+	qb.kinds = append(qb.kinds, kind)
+}
+
+// kindOf infers the kind of an argument that was not parsed from a filter.
+func kindOf(value any) parser.ValueKind {
+	switch value.(type) {
+	case string:
+		return parser.KindString
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return parser.KindInt
+	case float32, float64:
+		return parser.KindFloat
+	case bool:
+		return parser.KindBool
+	case time.Time:
+		return parser.KindDate
+	case []any:
+		return parser.KindArray
+	default:
+		return parser.KindInvalid
+	}
 }
@@ -1,7 +1,9 @@
 package builder
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -211,6 +213,22 @@ func TestQueryBuilder_ToSQL(t *testing.T) {
 		assert.Equal(t, "SELECT * FROM users WHERE status IN (?, ?)", sql)
 		assert.Len(t, args, 2)
 	})
+
+	t.Run("IN with negative numbers", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("score IN (-1, -2.5, 3)")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT * FROM users WHERE score IN (?, ?, ?)", sql)
+		assert.Equal(t, []any{-1, -2.5, 3}, args)
+	})
 }
 
 func TestQueryBuilder_Where(t *testing.T) {
@@ -225,11 +243,205 @@ func TestQueryBuilder_Where(t *testing.T) {
 		qb := NewQueryBuilder("users")
 		qb.SetFilter(filter)
 
-		whereSQL, args := qb.Where()
+		whereSQL, args, ok := qb.Where()
 
+		assert.True(t, ok)
 		assert.Equal(t, "(age > ? AND status = ?)", whereSQL)
 		assert.Len(t, args, 2)
 	})
+
+	t.Run("no filter has no predicate", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+
+		whereSQL, args, ok := qb.Where()
+
+		assert.False(t, ok)
+		assert.Empty(t, whereSQL)
+		assert.Nil(t, args)
+	})
+
+	t.Run("pruned conditions have no predicate", func(t *testing.T) {
+		t.Parallel()
+
+		// Bare predicates are pruned unless enabled
+		filter, err := parser.ParseFilter("active")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		whereSQL, args, ok := qb.Where()
+
+		assert.False(t, ok)
+		assert.Empty(t, whereSQL)
+		assert.Nil(t, args)
+	})
+
+	t.Run("honors numbered placeholders", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status IN ('active','pending')")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetFilter(filter)
+
+		whereSQL, args, ok := qb.Where()
+
+		assert.True(t, ok)
+		assert.Equal(t, "(age > $1 AND status IN ($2, $3))", whereSQL)
+		assert.Equal(t, []any{18, "active", "pending"}, args)
+
+		// Numbering restarts on every call
+		again, _, _ := qb.Where()
+		assert.Equal(t, whereSQL, again)
+	})
+}
+
+func TestQueryBuilder_WhereAppend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("leading AND with parentheses", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 || status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		clause, args := qb.WhereAppend()
+
+		assert.Equal(t, "AND (age > ? OR status = ?)", clause)
+		assert.Equal(t, []any{18, "active"}, args)
+	})
+
+	t.Run("single condition is parenthesized", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetPlaceholderStart(1)
+		qb.SetFilter(filter)
+
+		clause, args := qb.WhereAppend()
+
+		assert.Equal(t, "AND (age > $2)", clause)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("no filter is empty", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+
+		clause, args := qb.WhereAppend()
+
+		assert.Empty(t, clause)
+		assert.Nil(t, args)
+	})
+}
+
+func TestQueryBuilder_OrderByLimitOffset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns GORM-ready sort and pagination", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-created_at", "name"})
+		qb.SetDefaultSort("id")
+		qb.SetLimit(10)
+		qb.SetOffset(20)
+
+		assert.Equal(t, "created_at DESC, name ASC, id ASC", qb.OrderBy())
+		assert.Equal(t, 10, qb.Limit())
+		assert.Equal(t, 20, qb.Offset())
+	})
+
+	t.Run("empty when unset", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetOffset(-5)
+
+		assert.Empty(t, qb.OrderBy())
+		assert.Zero(t, qb.Limit())
+		assert.Zero(t, qb.Offset())
+	})
+
+	t.Run("skips sort fields that are not identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"id; DROP TABLE users--", "-name"})
+
+		assert.Equal(t, "name DESC", qb.OrderBy())
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid sort field 'id; DROP TABLE users--'")
+	})
+}
+
+func TestValidator_WhereOrderBy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns validated predicate and sort", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status IN ('active', 'trial')")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetSort([]string{"-created_at"})
+		v := qb.Validate(WithAllowedFields([]string{"age", "status", "created_at"}))
+
+		clause, args, ok, err := v.Where()
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "(age > ? AND status IN (?, ?))", clause)
+		assert.Equal(t, []any{18, "active", "trial"}, args)
+
+		orderBy, err := v.OrderBy()
+		require.NoError(t, err)
+		assert.Equal(t, "created_at DESC", orderBy)
+	})
+
+	t.Run("rejects disallowed fields", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"password_hash"})
+		v := qb.Validate(WithAllowedFields([]string{"id"}))
+
+		_, _, _, err := v.Where()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password_hash' is not allowed")
+
+		_, err = v.OrderBy()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password_hash' is not allowed")
+	})
+
+	t.Run("reports invalid sort expressions", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"id; DROP TABLE users--"})
+
+		orderBy, err := qb.Validate().OrderBy()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid sort field 'id; DROP TABLE users--'")
+		assert.Empty(t, orderBy)
+	})
 }
 
 func TestQueryBuilder_NoFilter(t *testing.T) {
@@ -251,6 +463,47 @@ func TestQueryBuilder_NoFilter(t *testing.T) {
 	})
 }
 
+func TestNewQueryBuilder_TableName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("surrounding whitespace is trimmed", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder(" users\t")
+		qb.SetDialect(DialectPostgres)
+		qb.SetQuoteIdentifiers(true)
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "users"`, sql)
+	})
+
+	t.Run("schema-qualified name", func(t *testing.T) {
+		t.Parallel()
+
+		sql, _, err := NewQueryBuilder("public.users").ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM public.users", sql)
+	})
+
+	t.Run("empty name is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := NewQueryBuilder("   ").ToSQL()
+		require.EqualError(t, err, "table name is empty")
+
+		_, _, err = NewQueryBuilder("").ToCountSQL()
+		require.EqualError(t, err, "table name is empty")
+	})
+
+	t.Run("invalid name is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := NewQueryBuilder("users; DROP TABLE users").ToSQL()
+		require.EqualError(t, err, "invalid table name 'users; DROP TABLE users'")
+	})
+}
+
 func TestQueryBuilder_ComplexNesting(t *testing.T) {
 	t.Parallel()
 
@@ -271,4 +524,3227 @@ func TestQueryBuilder_ComplexNesting(t *testing.T) {
 		assert.Contains(t, sql, "WHERE")
 		assert.Len(t, args, 4)
 	})
+
+	t.Run("OR group inside an AND chain", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := parser.ParseFilter("a=1 && (b=2 || c=3) && d=4")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("t")
+		qb.SetFilter(ast)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT * FROM t WHERE (a = ? AND (b = ? OR c = ?) AND d = ?)", sql)
+		assert.Equal(t, []any{1, 2, 3, 4}, args)
+	})
+
+	t.Run("three-level nesting", func(t *testing.T) {
+		t.Parallel()
+
+		ast, err := parser.ParseFilter("a=1 && (b=2 || (c=3 && (d=4 || e=5)))")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("t")
+		qb.SetFilter(ast)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT * FROM t WHERE (a = ? AND (b = ? OR (c = ? AND (d = ? OR e = ?))))", sql)
+		assert.Equal(t, []any{1, 2, 3, 4, 5}, args)
+	})
+
+	parens := []struct {
+		name     string
+		filter   string
+		expected string
+	}{
+		{"single comparison group mixed with AND/OR", "(a=1) || b=2 && c=3", "SELECT * FROM t WHERE ((a = ?) OR (b = ? AND c = ?))"},
+		{"group inside the AND branch", "a=1 || (b=2) && c=3", "SELECT * FROM t WHERE (a = ? OR ((b = ?) AND c = ?))"},
+		{"redundant parentheses are collapsed", "((a=1)) || b=2 && c=3", "SELECT * FROM t WHERE ((a = ?) OR (b = ? AND c = ?))"},
+		{"AND-only level drops them", "(a=1) && b=2", "SELECT * FROM t WHERE (a = ? AND b = ?)"},
+		{"OR-only level drops them", "(a=1) || b=2", "SELECT * FROM t WHERE (a = ? OR b = ?)"},
+		{"whole filter", "(a=1)", "SELECT * FROM t WHERE a = ?"},
+	}
+
+	for _, tc := range parens {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ast, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("t")
+			qb.SetFilter(ast)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+			assert.NotEmpty(t, args)
+		})
+	}
+}
+
+func TestQueryBuilder_ToNamedSQL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("emits :pN tokens with matching named args", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status IN ('active', 'pending')")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetPlaceholder(":p0")
+
+		sql, args, err := qb.ToNamedSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT * FROM users WHERE (age > :p0 AND status IN (:p1, :p2))", sql)
+		require.Len(t, args, 3)
+		assert.Equal(t, "p0", args[0].Name)
+		assert.Equal(t, 18, args[0].Value)
+		assert.Equal(t, "p1", args[1].Name)
+		assert.Equal(t, "active", args[1].Value)
+		assert.Equal(t, "p2", args[2].Name)
+		assert.Equal(t, "pending", args[2].Value)
+	})
+
+	t.Run("ToSQL returns ordered args with :pN tokens", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetPlaceholder(":p0")
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT * FROM users WHERE (age > :p0 AND status = :p1)", sql)
+		assert.Equal(t, []any{18, "active"}, args)
+	})
+
+	t.Run("rejects non-named placeholder style", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+
+		_, _, err := qb.ToNamedSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "named arguments require placeholder style")
+	})
+}
+
+func TestQueryBuilder_BarePredicates(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		filter       string
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{"bare field", "active", "SELECT * FROM users WHERE active = ?", []any{true}},
+		{"negated bare field", "!active", "SELECT * FROM users WHERE active = ?", []any{false}},
+		{"combined with comparison", "active && age>18", "SELECT * FROM users WHERE (active = ? AND age > ?)", []any{true, 18}},
+		{"negated group", "!(active || age>18)", "SELECT * FROM users WHERE NOT (active = ? OR age > ?)", []any{true, 18}},
+		{"negated comparison", "!age>18", "SELECT * FROM users WHERE NOT (age > ?)", []any{18}},
+		{"negated equality", "!a=1 && active", "SELECT * FROM users WHERE (NOT (a = ?) AND active = ?)", []any{1, true}},
+		{"negated null check", "!deleted_at IS NULL", "SELECT * FROM users WHERE NOT (deleted_at IS NULL)", []any{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetBarePredicates(true)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("active && age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE age > ?", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("negated comparison without bare predicates", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("!age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE NOT (age > ?)", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("negated bare field requires bare predicates", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("!active")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "negated field 'active' requires bare predicates to be enabled")
+	})
+}
+
+func TestQueryBuilder_ILike(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		filter      string
+		dialect     Dialect
+		expectedSQL string
+	}{
+		{"ILIKE on postgres", "name ILIKE '%john%'", DialectPostgres, "SELECT * FROM users WHERE name ILIKE ?"},
+		{"NOT ILIKE on postgres", "email NOT ILIKE '%test%'", DialectPostgres, "SELECT * FROM users WHERE email NOT ILIKE ?"},
+		{"not ilike lowercase on postgres", "email not ilike '%test%'", DialectPostgres, "SELECT * FROM users WHERE email NOT ILIKE ?"},
+		{"ILIKE on mysql", "name ILIKE '%john%'", DialectMySQL, "SELECT * FROM users WHERE LOWER(name) LIKE LOWER(?)"},
+		{"NOT ILIKE on generic", "email NOT ILIKE '%test%'", DialectGeneric, "SELECT * FROM users WHERE LOWER(email) NOT LIKE LOWER(?)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Len(t, args, 1)
+		})
+	}
+}
+
+func TestQueryBuilder_Tilde(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		filter      string
+		ilike       bool
+		dialect     Dialect
+		expectedSQL string
+	}{
+		{"~ is LIKE", "name~'John'", false, DialectGeneric, "SELECT * FROM users WHERE name LIKE ?"},
+		{"!~ is NOT LIKE", "name!~'John'", false, DialectGeneric, "SELECT * FROM users WHERE name NOT LIKE ?"},
+		{"~ as ILIKE on postgres", "name~'John'", true, DialectPostgres, "SELECT * FROM users WHERE name ILIKE ?"},
+		{"!~ as NOT ILIKE on postgres", "name!~'John'", true, DialectPostgres, "SELECT * FROM users WHERE name NOT ILIKE ?"},
+		{"~ as ILIKE on mysql", "name~'John'", true, DialectMySQL, "SELECT * FROM users WHERE LOWER(name) LIKE LOWER(?)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+			qb.SetTildeILike(tc.ilike)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, []any{"John"}, args)
+		})
+	}
+
+	t.Run("operator rules apply to the resolved operator", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("name~'John'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetTildeILike(true)
+
+		_, _, err = qb.Validate(WithForbiddenOperators("ILIKE")).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operator 'ILIKE' is not allowed")
+	})
+}
+
+func TestQueryBuilder_Dates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("date bounds are bound as time.Time", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("created_at>=2024-01-01 && created_at<2024-02-01T00:00:00Z")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM orders WHERE (created_at >= ? AND created_at < ?)", sql)
+		assert.Equal(t, []any{
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		}, args)
+	})
+
+	t.Run("date strings binds normalized strings", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("created_at>=2024-01-01 && created_at<2024-02-01T00:00:00.000+00:00")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFilter(filter)
+		qb.SetDateStrings(true)
+
+		_, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, []any{"2024-01-01", "2024-02-01T00:00:00Z"}, args)
+	})
+}
+
+func TestQueryBuilder_Contains(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scalar value is encoded as JSON array", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("tags CONTAINS 'go'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("posts")
+		qb.SetFilter(filter)
+		qb.SetDialect(DialectPostgres)
+		qb.SetPlaceholder("$1")
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM posts WHERE tags @> $1", sql)
+		assert.Equal(t, []any{`["go"]`}, args)
+	})
+
+	t.Run("array value is encoded as JSON array", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("tags CONTAINS ('go', 'sql') && score>3")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("posts")
+		qb.SetFilter(filter)
+		qb.SetDialect(DialectPostgres)
+		qb.SetPlaceholder("$1")
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM posts WHERE (tags @> $1 AND score > $2)", sql)
+		assert.Equal(t, []any{`["go","sql"]`, 3}, args)
+	})
+
+	t.Run("rejected for dialects without JSON support", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("tags CONTAINS 'go'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("posts")
+		qb.SetFilter(filter)
+		qb.SetDialect(DialectMySQL)
+
+		sql, args, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operator CONTAINS on field 'tags' requires the postgres dialect")
+		assert.Empty(t, sql)
+		assert.Nil(t, args)
+	})
+}
+
+func TestQueryBuilder_MinimalParens(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		filter     string
+		defaultSQL string
+		minimalSQL string
+	}{
+		{
+			"pure AND",
+			"age>18 && status='active'",
+			"SELECT * FROM users WHERE (age > ? AND status = ?)",
+			"SELECT * FROM users WHERE age > ? AND status = ?",
+		},
+		{
+			"pure OR",
+			"role='admin' || role='owner' || verified=true",
+			"SELECT * FROM users WHERE (role = ? OR role = ? OR verified = ?)",
+			"SELECT * FROM users WHERE role = ? OR role = ? OR verified = ?",
+		},
+		{
+			"deeply nested groups keep parens",
+			"age>18 || (status='active' && (role='admin' || role='owner'))",
+			"SELECT * FROM users WHERE (age > ? OR (status = ? AND (role = ? OR role = ?)))",
+			"SELECT * FROM users WHERE age > ? OR (status = ? AND (role = ? OR role = ?))",
+		},
+		{
+			"negated top-level group keeps parens",
+			"!(role='admin' || role='owner')",
+			"SELECT * FROM users WHERE NOT (role = ? OR role = ?)",
+			"SELECT * FROM users WHERE NOT (role = ? OR role = ?)",
+		},
+		{
+			"mixed AND/OR keeps nested parens",
+			"age>18 && status='active' || role='admin'",
+			"SELECT * FROM users WHERE ((age > ? AND status = ?) OR role = ?)",
+			"SELECT * FROM users WHERE (age > ? AND status = ?) OR role = ?",
+		},
+		{
+			"OR group inside AND keeps parens",
+			"(age>18 || role='admin') && (status='active' || verified=true)",
+			"SELECT * FROM users WHERE ((age > ? OR role = ?) AND (status = ? OR verified = ?))",
+			"SELECT * FROM users WHERE (age > ? OR role = ?) AND (status = ? OR verified = ?)",
+		},
+		{
+			"single comparison is unchanged",
+			"age>18",
+			"SELECT * FROM users WHERE age > ?",
+			"SELECT * FROM users WHERE age > ?",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			sql, _, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.defaultSQL, sql)
+
+			qb.SetMinimalParens(true)
+			sql, _, err = qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.minimalSQL, sql)
+		})
+	}
+}
+
+func TestQueryBuilder_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		filter      string
+		dialect     Dialect
+		expectedSQL string
+	}{
+		{"NOT REGEXP on postgres", "email NOT REGEXP '@test\\.'", DialectPostgres, "SELECT * FROM users WHERE email !~ ?"},
+		{"NOT REGEXP on mysql", "email not regexp '@test\\.'", DialectMySQL, "SELECT * FROM users WHERE email NOT REGEXP ?"},
+		{"REGEXP on postgres", "email REGEXP '@test\\.'", DialectPostgres, "SELECT * FROM users WHERE email ~ ?"},
+		{"REGEXP on mysql", "email REGEXP '@test\\.'", DialectMySQL, "SELECT * FROM users WHERE email REGEXP ?"},
+		{"NOT REGEXP on oracle", "email NOT REGEXP '@test\\.'", DialectOracle, "SELECT * FROM users WHERE NOT REGEXP_LIKE(email, ?)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, []any{`@test\.`}, args)
+		})
+	}
+}
+
+func TestQueryBuilder_NullSafeEqual(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		dialect     Dialect
+		expectedSQL string
+	}{
+		{"mysql", DialectMySQL, "SELECT * FROM users WHERE manager_id <=> ?"},
+		{"postgres", DialectPostgres, "SELECT * FROM users WHERE manager_id IS NOT DISTINCT FROM ?"},
+		{"sqlite", DialectSQLite, "SELECT * FROM users WHERE manager_id IS ?"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter("manager_id <=> 5")
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, []any{5}, args)
+		})
+	}
+
+	t.Run("null literal maps to IS NULL", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("manager_id <=> null")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetDialect(DialectMySQL)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE manager_id IS NULL", sql)
+		assert.Empty(t, args)
+	})
+}
+
+func TestQueryBuilder_DistinctFrom(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		filter      string
+		dialect     Dialect
+		expectedSQL string
+	}{
+		{"distinct on postgres", "status IS DISTINCT FROM 'active'", DialectPostgres, "SELECT * FROM users WHERE status IS DISTINCT FROM ?"},
+		{"not distinct on postgres", "status IS NOT DISTINCT FROM 'active'", DialectPostgres, "SELECT * FROM users WHERE status IS NOT DISTINCT FROM ?"},
+		{"distinct on mysql", "status IS DISTINCT FROM 'active'", DialectMySQL, "SELECT * FROM users WHERE NOT (status <=> ?)"},
+		{"not distinct on mysql", "status IS NOT DISTINCT FROM 'active'", DialectMySQL, "SELECT * FROM users WHERE status <=> ?"},
+		{"distinct on sqlite", "status IS DISTINCT FROM 'active'", DialectSQLite, "SELECT * FROM users WHERE status IS NOT ?"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, []any{"active"}, args)
+		})
+	}
+
+	t.Run("null literal maps to IS NULL checks", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("deleted_at IS DISTINCT FROM null && manager_id IS NOT DISTINCT FROM null")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (deleted_at IS NOT NULL AND manager_id IS NULL)", sql)
+		assert.Empty(t, args)
+	})
+}
+
+func TestQueryBuilder_Select(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default selects all fields", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+
+		sql, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users", sql)
+	})
+
+	t.Run("explicit fields without filter, sort or pagination", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"id", "name"})
+		qb.SetFilter(filter)
+		qb.SetSort([]string{"-id"})
+		qb.SetLimit(10)
+
+		sql, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name FROM users", sql)
+	})
+
+	t.Run("reports build errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			qb      *QueryBuilder
+			wantErr string
+		}{
+			{"invalid table", NewQueryBuilder("users; --"), "invalid table name 'users; --'"},
+			{"distinct on without postgres", NewQueryBuilder("users").SetDistinctOn("email"), "DISTINCT ON requires the postgres dialect"},
+			{"invalid aggregate", NewQueryBuilder("users").SetFields([]string{"sum(id; DROP)"}), "sum(id; DROP)"},
+		}
+
+		for _, tc := range tests {
+			sql, err := tc.qb.Select()
+			require.Error(t, err, tc.name)
+			assert.Contains(t, err.Error(), tc.wantErr, tc.name)
+			assert.Empty(t, sql, tc.name)
+		}
+	})
+}
+
+func TestQueryBuilder_SetRawWhere(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renumbers $n placeholders after the parsed filter", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetPlaceholder("$1")
+		qb.SetRawWhere("EXISTS (SELECT 1 FROM orders o WHERE o.user_id = users.id AND o.total > $1 AND o.status = $2)", 100, "paid")
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE ((age > $1 AND status = $2) AND (EXISTS (SELECT 1 FROM orders o WHERE o.user_id = users.id AND o.total > $3 AND o.status = $4)))", sql)
+		assert.Equal(t, []any{18, "active", 100, "paid"}, args)
+	})
+
+	t.Run("? placeholders with default style", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetRawWhere("tenant_id = ? AND name <> '?'", 7)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND (tenant_id = ? AND name <> '?'))", sql)
+		assert.Equal(t, []any{18, 7}, args)
+	})
+
+	t.Run("raw predicate without filter", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetRawWhere("created_at > now() - $1::interval", "7 days")
+
+		clause, args, ok := qb.Where()
+		assert.True(t, ok)
+		assert.Equal(t, "(created_at > now() - $1::interval)", clause)
+		assert.Equal(t, []any{"7 days"}, args)
+	})
+
+	t.Run("missing argument fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetRawWhere("a = $1 AND b = $2", 1)
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "raw where placeholder 2 has no matching argument")
+	})
+}
+
+func TestQueryBuilder_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		filter       string
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{"modulo", "id % 2 = 0", "SELECT * FROM products WHERE id % 2 = ?", []any{0}},
+		{"multiplication", "price * 2 > 100", "SELECT * FROM products WHERE price * 2 > ?", []any{100}},
+		{"subtraction without spaces", "stock-1 > 0", "SELECT * FROM products WHERE stock - 1 > ?", []any{0}},
+		{"field operand", "price * quantity > 1000 && active=true", "SELECT * FROM products WHERE (price * quantity > ? AND active = ?)", []any{1000, true}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("products")
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestQueryBuilder_ReusedArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("args from a prior build don't leak into the next", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("status IN ('active', 'pending', 'trial')")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Len(t, args, 3)
+
+		filter, err = parser.ParseFilter("age>18")
+		require.NoError(t, err)
+		qb.SetFilter(filter)
+
+		_, args, err = qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("count and data builds return the same args", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, countArgs, ok := qb.Where()
+		require.True(t, ok)
+		countArgs = append(countArgs, "extra")
+
+		_, dataArgs, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, []any{18, "active"}, dataArgs)
+		assert.Equal(t, []any{18, "active", "extra"}, countArgs)
+	})
+
+	t.Run("later builds don't overwrite returned args", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("active=true && age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, dataArgs, err := qb.ToSQL()
+		require.NoError(t, err)
+		_, whereArgs, ok := qb.Where()
+		require.True(t, ok)
+
+		_, _, err = qb.ToSQLFor(DialectMySQL)
+		require.NoError(t, err)
+
+		filter, err = parser.ParseFilter("name='x' && deleted_at IS NOT NULL")
+		require.NoError(t, err)
+		qb.SetFilter(filter)
+		_, _, err = qb.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{true, 18}, dataArgs)
+		assert.Equal(t, []any{true, 18}, whereArgs)
+	})
+}
+
+func BenchmarkQueryBuilder_CountAndData(b *testing.B) {
+	filter, err := parser.ParseFilter("(age>18 && status='active') || role IN ('admin', 'owner')")
+	require.NoError(b, err)
+
+	qb := NewQueryBuilder("users")
+	qb.SetFilter(filter)
+	qb.SetFields([]string{"id", "name", "email"})
+	qb.SetSort([]string{"-created_at"})
+	qb.SetLimit(20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Count query
+		if _, _, ok := qb.Where(); !ok {
+			b.Fatal("expected predicate")
+		}
+		// Data query
+		if _, _, err := qb.ToSQL(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestQueryBuilder_NullLiteral(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		filter      string
+		expectedSQL string
+	}{
+		{"equal null", "status = null", "SELECT * FROM users WHERE status IS NULL"},
+		{"not equal null", "status != null", "SELECT * FROM users WHERE status IS NOT NULL"},
+		{"diamond null", "status <> NULL", "SELECT * FROM users WHERE status IS NOT NULL"},
+		{"combined with comparison", "age>18 && deleted_at = null", "SELECT * FROM users WHERE (age > ? AND deleted_at IS NULL)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.NotContains(t, args, nil)
+		})
+	}
+
+	t.Run("ordering operator with null fails", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age > null")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operator > on field 'age' cannot compare with NULL")
+	})
+}
+
+func TestQueryBuilder_SetTableAlias(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unqualified fields get the alias prefix", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && price * quantity > 100")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetTableAlias("u")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"id", "name"})
+		qb.SetSort([]string{"-COALESCE(updated_at,created_at)", "id"})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT u.id, u.name FROM users AS u WHERE (u.age > ? AND u.price * u.quantity > ?) ORDER BY COALESCE(u.updated_at, u.created_at) DESC, u.id ASC", sql)
+		assert.Equal(t, []any{18, 100}, args)
+	})
+
+	t.Run("already-qualified fields are left untouched", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("p.verified=true && age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetTableAlias("u")
+		qb.InnerJoin("profiles p", "u.id = p.user_id")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"u.id", "p.bio"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT u.id, p.bio FROM users AS u INNER JOIN profiles p ON u.id = p.user_id WHERE (p.verified = ? AND u.age > ?)", sql)
+	})
+}
+
+func TestQueryBuilder_ToTypedSQL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("kinds line up with args", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && name='john' && active=true && score>=4.5")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToTypedSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND name = ? AND active = ? AND score >= ?)", sql)
+		assert.Equal(t, []Arg{
+			{Value: 18, Kind: parser.KindInt},
+			{Value: "john", Kind: parser.KindString},
+			{Value: true, Kind: parser.KindBool},
+			{Value: 4.5, Kind: parser.KindFloat},
+		}, args)
+	})
+
+	t.Run("raw where args are inferred from their type", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("id IN (1,2)")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetRawWhere("tenant_id = ?", "acme")
+
+		_, args, err := qb.ToTypedSQL()
+		require.NoError(t, err)
+		assert.Equal(t, []Arg{
+			{Value: 1, Kind: parser.KindInt},
+			{Value: 2, Kind: parser.KindInt},
+			{Value: "acme", Kind: parser.KindString},
+		}, args)
+	})
+}
+
+func TestQueryBuilder_NumberedPlaceholderOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		filter       string
+		placeholder  string
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:         "IN then scalar",
+			filter:       "status IN ('a','b') && age>18",
+			placeholder:  "$1",
+			expectedSQL:  "SELECT * FROM users WHERE (status IN ($1, $2) AND age > $3)",
+			expectedArgs: []any{"a", "b", 18},
+		},
+		{
+			name:         "scalar then IN",
+			filter:       "age>18 && status IN ('a','b')",
+			placeholder:  "$1",
+			expectedSQL:  "SELECT * FROM users WHERE (age > $1 AND status IN ($2, $3))",
+			expectedArgs: []any{18, "a", "b"},
+		},
+		{
+			name:         "IN between scalars across OR",
+			filter:       "age>18 || id NOT IN (1,2,3) || name='x'",
+			placeholder:  "$1",
+			expectedSQL:  "SELECT * FROM users WHERE (age > $1 OR id NOT IN ($2, $3, $4) OR name = $5)",
+			expectedArgs: []any{18, 1, 2, 3, "x"},
+		},
+		{
+			name:         "IN with null then range",
+			filter:       "status IN ('a',null) && age=18..65",
+			placeholder:  "$1",
+			expectedSQL:  "SELECT * FROM users WHERE ((status IN ($1) OR status IS NULL) AND age BETWEEN $2 AND $3)",
+			expectedArgs: []any{"a", 18, 65},
+		},
+		{
+			name:         "SQL Server style",
+			filter:       "status IN ('a','b') && age>18",
+			placeholder:  "@p1",
+			expectedSQL:  "SELECT * FROM users WHERE (status IN (@p1, @p2) AND age > @p3)",
+			expectedArgs: []any{"a", "b", 18},
+		},
+		{
+			name:         "named style",
+			filter:       "age>18 && status IN ('a','b')",
+			placeholder:  ":p0",
+			expectedSQL:  "SELECT * FROM users WHERE (age > :p0 AND status IN (:p1, :p2))",
+			expectedArgs: []any{18, "a", "b"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetPlaceholder(tc.placeholder)
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+
+			// Rebuilding numbers from the start again
+			sql, _, err = qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+		})
+	}
+}
+
+func TestQueryBuilder_SetPlaceholderStart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("numbered placeholders begin after start", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetPlaceholderStart(2)
+		qb.SetFilter(filter)
+
+		clause, args, ok := qb.Where()
+		require.True(t, ok)
+		assert.Equal(t, "(age > $3 AND status = $4)", clause)
+		assert.Equal(t, []any{18, "active"}, args)
+
+		// Repeated builds start from the same offset
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > $3 AND status = $4)", sql)
+	})
+
+	t.Run("named args use the offset names", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder(":p0")
+		qb.SetPlaceholderStart(1)
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToNamedSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE age > :p1", sql)
+		require.Len(t, args, 1)
+		assert.Equal(t, "p1", args[0].Name)
+	})
+
+	t.Run("positional placeholders are unaffected", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholderStart(2)
+		qb.SetFilter(filter)
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND status = ?)", sql)
+	})
+}
+
+func TestQueryBuilder_OffsetWithoutLimit(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		dialect     Dialect
+		limit       int
+		expectedSQL string
+	}{
+		{"postgres keeps bare OFFSET", DialectPostgres, 0, "SELECT * FROM users OFFSET 20"},
+		{"mysql adds sentinel LIMIT", DialectMySQL, 0, "SELECT * FROM users LIMIT 18446744073709551615 OFFSET 20"},
+		{"mysql keeps explicit LIMIT", DialectMySQL, 10, "SELECT * FROM users LIMIT 10 OFFSET 20"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("users")
+			qb.SetDialect(tc.dialect)
+			qb.SetLimit(tc.limit)
+			qb.SetOffset(20)
+
+			sql, _, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+		})
+	}
+}
+
+func TestQueryBuilder_SetInSubquery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("subquery args are interleaved after the filter", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetFilter(filter)
+		qb.SetInSubquery("id", "SELECT user_id FROM admins WHERE org_id = ? AND level >= ?", 7, 2)
+		qb.SetRawWhere("tenant_id = ?", "acme")
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE ((age > $1 AND status = $2) AND id IN (SELECT user_id FROM admins WHERE org_id = $3 AND level >= $4) AND (tenant_id = $5))", sql)
+		assert.Equal(t, []any{18, "active", 7, 2, "acme"}, args)
+	})
+
+	t.Run("subquery alone with positional args", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetInSubquery("id", "SELECT user_id FROM admins")
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE id IN (SELECT user_id FROM admins)", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("missing subquery arg fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetInSubquery("id", "SELECT user_id FROM admins WHERE org_id = ?")
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "subquery placeholder 1 has no matching argument")
+	})
+}
+
+func TestQueryBuilder_SetArrayParams(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		filter       string
+		dialect      Dialect
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{"IN on postgres", "status IN ('active','pending')", DialectPostgres, "SELECT * FROM users WHERE status = ANY($1)", []any{[]any{"active", "pending"}}},
+		{"NOT IN on postgres", "id NOT IN (1,2,3)", DialectPostgres, "SELECT * FROM users WHERE id != ALL($1)", []any{[]any{1, 2, 3}}},
+		{"IN on mysql stays expanded", "status IN ('active','pending')", DialectMySQL, "SELECT * FROM users WHERE status IN ($1, $2)", []any{"active", "pending"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+			qb.SetPlaceholder("$1")
+			qb.SetArrayParams(true)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestQueryBuilder_ToSQLDebug(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pairs numbered placeholders with values", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status IN ('active','pending')")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQLDebug()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > $1 AND status IN ($2, $3))", sql)
+		assert.Equal(t, []DebugArg{
+			{Placeholder: "$1", Value: 18},
+			{Placeholder: "$2", Value: "active"},
+			{Placeholder: "$3", Value: "pending"},
+		}, args)
+		assert.Equal(t, "[$1=18 $2='active' $3='pending']", fmt.Sprint(args))
+
+		// ToSQL output is unchanged
+		plain, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, sql, plain)
+	})
+
+	t.Run("positional and named styles", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, args, err := qb.ToSQLDebug()
+		require.NoError(t, err)
+		assert.Equal(t, []DebugArg{{Placeholder: "?", Value: 18}}, args)
+
+		qb.SetPlaceholder(":p0")
+		qb.SetPlaceholderStart(1)
+
+		_, args, err = qb.ToSQLDebug()
+		require.NoError(t, err)
+		assert.Equal(t, []DebugArg{{Placeholder: ":p1", Value: 18}}, args)
+	})
+}
+
+func TestQueryBuilder_BooleanArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		filter       string
+		dialect      Dialect
+		expectedArgs []any
+	}{
+		{"postgres keeps native booleans", "verified=true", DialectPostgres, []any{true}},
+		{"generic keeps native booleans", "verified=true", DialectGeneric, []any{true}},
+		{"sqlite binds 1", "verified=true", DialectSQLite, []any{1}},
+		{"mysql binds 0", "verified=false", DialectMySQL, []any{0}},
+		{"sqlite bare predicates", "active && !banned", DialectSQLite, []any{1, 0}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			qb.SetDialect(tc.dialect)
+			qb.SetBarePredicates(true)
+
+			_, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestQueryBuilder_SortDirection(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		sort        []string
+		expectedSQL string
+	}{
+		{"uppercase ASC", []string{"name:ASC"}, "SELECT * FROM users ORDER BY name ASC"},
+		{"mixed case Desc", []string{"created_at:Desc"}, "SELECT * FROM users ORDER BY created_at DESC"},
+		{"function with direction", []string{"COALESCE(updated_at,created_at):desc"}, "SELECT * FROM users ORDER BY COALESCE(updated_at, created_at) DESC"},
+		{"mixed with prefix syntax", []string{"-age", "name:asc"}, "SELECT * FROM users ORDER BY age DESC, name ASC"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("users")
+			qb.SetSort(tc.sort)
+
+			sql, _, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+		})
+	}
+
+	t.Run("unknown direction fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"name:downward"})
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Equal(t, "invalid sort direction 'downward' in 'name:downward': use asc or desc", err.Error())
+	})
+
+	t.Run("prefix and suffix together fail", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-name:asc"})
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "use either '-' or a direction suffix")
+	})
+
+	t.Run("default sort is not duplicated", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"id:desc"})
+		qb.SetDefaultSort("id")
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY id DESC", sql)
+	})
+}
+
+func TestQueryBuilder_EnsureFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fields   []string
+		ensure   []string
+		expected string
+	}{
+		{"appends missing field", []string{"name"}, []string{"id"}, "SELECT name, id FROM users"},
+		{"no duplication", []string{"id", "name"}, []string{"id"}, "SELECT id, name FROM users"},
+		{"select all is unchanged", nil, []string{"id"}, "SELECT * FROM users"},
+		{"several fields", []string{"name"}, []string{"id", "name", "tenant_id"}, "SELECT name, id, tenant_id FROM users"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("users")
+			qb.SetFields(tc.fields)
+			qb.EnsureFields(tc.ensure...)
+
+			sql, _, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+			assert.Equal(t, tc.fields, qb.fields)
+		})
+	}
+
+	t.Run("validated against the allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"name"})
+
+		_, _, err := qb.Validate(
+			WithAllowedFields([]string{"name"}),
+			WithEnsureFields("id"),
+		).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'id' is not allowed")
+	})
+
+	t.Run("validated with case-insensitive fields", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"Name"})
+
+		sql, _, err := qb.Validate(
+			WithAllowedFields([]string{"id", "name"}),
+			WithCaseInsensitiveFields(),
+			WithEnsureFields("ID"),
+		).ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT name, id FROM users", sql)
+	})
+}
+
+func TestQueryBuilder_SortPositions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("positions within the selected fields", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFields([]string{"customer_id", "total"})
+		qb.SetSort([]string{"-2", "1"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT customer_id, total FROM orders ORDER BY 2 DESC, 1 ASC", sql)
+	})
+
+	t.Run("validated positions skip the allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFields([]string{"customer_id", "total"})
+		qb.SetSort([]string{"2:desc", "customer_id"})
+
+		sql, _, err := qb.Validate(WithAllowedFields([]string{"customer_id", "total"})).ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT customer_id, total FROM orders ORDER BY 2 DESC, customer_id ASC", sql)
+	})
+
+	t.Run("out of range position fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFields([]string{"customer_id", "total"})
+		qb.SetSort([]string{"3"})
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Equal(t, "sort position 3 is out of range: 2 fields selected", err.Error())
+	})
+
+	t.Run("position without selected fields fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetSort([]string{"1"})
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Equal(t, "sort position 1 is out of range: 0 fields selected", err.Error())
+	})
+
+	t.Run("zero position fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFields([]string{"customer_id"})
+		qb.SetSort([]string{"-0"})
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Equal(t, "invalid sort position '-0'", err.Error())
+	})
+}
+
+func TestQueryBuilder_AddInCondition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("int slice follows filter args", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetFilter(filter)
+		qb.AddInCondition("id", []any{4, 8, 15})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE ((age > $1 AND status = $2) AND id IN ($3, $4, $5))", sql)
+		assert.Equal(t, []any{18, "active", 4, 8, 15}, args)
+	})
+
+	t.Run("string slice without filter", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.AddInCondition("role", []any{"admin", "owner"})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE role IN (?, ?)", sql)
+		assert.Equal(t, []any{"admin", "owner"}, args)
+	})
+
+	t.Run("array param on postgres", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetDialect(DialectPostgres)
+		qb.SetPlaceholder("$1")
+		qb.SetArrayParams(true)
+		qb.AddInCondition("id", []any{1, 2})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE id = ANY($1)", sql)
+		assert.Equal(t, []any{[]any{1, 2}}, args)
+	})
+
+	t.Run("empty slice matches no rows", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.AddInCondition("id", nil)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE 1 = 0", sql)
+		assert.Empty(t, args)
+	})
+}
+
+func TestQueryBuilder_InWithNull(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		filter       string
+		arrayParams  bool
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:         "IN with null matches IS NULL",
+			filter:       "status IN ('active', null)",
+			expectedSQL:  "SELECT * FROM users WHERE (status IN ($1) OR status IS NULL)",
+			expectedArgs: []any{"active"},
+		},
+		{
+			name:         "NOT IN with null excludes NULL",
+			filter:       "status NOT IN ('active', 'pending', NULL)",
+			expectedSQL:  "SELECT * FROM users WHERE (status NOT IN ($1, $2) AND status IS NOT NULL)",
+			expectedArgs: []any{"active", "pending"},
+		},
+		{
+			name:         "IN with only null",
+			filter:       "status IN (null)",
+			expectedSQL:  "SELECT * FROM users WHERE status IS NULL",
+			expectedArgs: []any{},
+		},
+		{
+			name:         "NOT IN with only null",
+			filter:       "status NOT IN (null)",
+			expectedSQL:  "SELECT * FROM users WHERE status IS NOT NULL",
+			expectedArgs: []any{},
+		},
+		{
+			name:         "array param with null",
+			filter:       "id IN (1, null, 2)",
+			arrayParams:  true,
+			expectedSQL:  "SELECT * FROM users WHERE (id = ANY($1) OR id IS NULL)",
+			expectedArgs: []any{[]any{1, 2}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetDialect(DialectPostgres)
+			qb.SetPlaceholder("$1")
+			qb.SetArrayParams(tc.arrayParams)
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+			assert.Equal(t, len(tc.expectedArgs), qb.ArgCount())
+		})
+	}
+
+	t.Run("nil in DSL list", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(parser.F("status").In("active", nil))
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (status IN (?) OR status IS NULL)", sql)
+		assert.Equal(t, []any{"active"}, args)
+	})
+}
+
+func TestQueryBuilder_SetQuoteIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clickhouse uses backticks and ? placeholders", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("event_type='click' && user_id IN (1,2)")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("events")
+		qb.SetDialect(DialectClickHouse)
+		qb.SetQuoteIdentifiers(true)
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"user_id", "event_type"})
+		qb.SetSort([]string{"-timestamp"})
+		qb.SetLimit(10)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT `user_id`, `event_type` FROM `events` WHERE (`event_type` = ? AND `user_id` IN (?, ?)) ORDER BY `timestamp` DESC LIMIT 10", sql)
+		assert.Equal(t, []any{"click", 1, 2}, args)
+	})
+
+	t.Run("postgres uses double quotes for qualified names", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetDialect(DialectPostgres)
+		qb.SetQuoteIdentifiers(true)
+		qb.SetTableAlias("u")
+		qb.SetFilter(filter)
+		qb.SetSort([]string{"LOWER(name)"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "users" AS "u" WHERE "u"."age" > ? ORDER BY LOWER("u"."name") ASC`, sql)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("events")
+		qb.SetDialect(DialectClickHouse)
+		qb.SetFields([]string{"user_id"})
+
+		sql, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT user_id FROM events", sql)
+	})
+}
+
+func TestQueryBuilder_ToSQLInline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   string
+		dialect  Dialect
+		expected string
+	}{
+		{
+			name:     "escapes single quotes in strings",
+			filter:   `name="O'Brien"`,
+			expected: "SELECT * FROM users WHERE name = 'O''Brien'",
+		},
+		{
+			name:     "numbers are raw",
+			filter:   "age>18 && score<=9.5",
+			expected: "SELECT * FROM users WHERE (age > 18 AND score <= 9.5)",
+		},
+		{
+			name:     "booleans are raw",
+			filter:   "active=true && deleted=false",
+			expected: "SELECT * FROM users WHERE (active = TRUE AND deleted = FALSE)",
+		},
+		{
+			name:     "booleans as integers for MySQL",
+			filter:   "active=true",
+			dialect:  DialectMySQL,
+			expected: "SELECT * FROM users WHERE active = 1",
+		},
+		{
+			name:     "IN list",
+			filter:   "status IN ('active','pending') && id NOT IN (1,2)",
+			expected: "SELECT * FROM users WHERE (status IN ('active', 'pending') AND id NOT IN (1, 2))",
+		},
+		{
+			name:     "backslashes escaped for MySQL",
+			filter:   `path='C:\tmp'`,
+			dialect:  DialectMySQL,
+			expected: `SELECT * FROM users WHERE path = 'C:\\tmp'`,
+		},
+		{
+			name:     "dates are quoted",
+			filter:   "created_at>=2024-01-01",
+			expected: "SELECT * FROM users WHERE created_at >= '2024-01-01T00:00:00Z'",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetPlaceholder("$1")
+			qb.SetDialect(tc.dialect)
+			qb.SetFilter(filter)
+
+			sql, err := qb.ToSQLInline()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+
+			// ToSQL still binds placeholders afterwards
+			plain, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Contains(t, plain, "$1")
+			assert.NotEmpty(t, args)
+		})
+	}
+
+	t.Run("raw clause arguments are inlined", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetRawWhere("tenant_id = ? AND name != 'a?b'", 7)
+
+		sql, err := qb.ToSQLInline()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (tenant_id = 7 AND name != 'a?b')", sql)
+	})
+}
+
+func TestQueryBuilder_ToSQLFor(t *testing.T) {
+	t.Parallel()
+
+	filter, err := parser.ParseFilter("age>18 && name ILIKE 'jo%'")
+	require.NoError(t, err)
+
+	qb := NewQueryBuilder("users")
+	qb.SetFilter(filter)
+	qb.SetSort([]string{"-id"})
+	qb.SetLimit(10)
+
+	sql, args, err := qb.ToSQLFor(DialectPostgres)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE ("age" > $1 AND "name" ILIKE $2) ORDER BY "id" DESC LIMIT 10`, sql)
+	assert.Equal(t, []any{18, "jo%"}, args)
+
+	sql, args, err = qb.ToSQLFor(DialectMySQL)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `users` WHERE (`age` > ? AND LOWER(`name`) LIKE LOWER(?)) ORDER BY `id` DESC LIMIT 10", sql)
+	assert.Equal(t, []any{18, "jo%"}, args)
+
+	// The builder's own configuration is unchanged
+	sql, _, err = qb.ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND LOWER(name) LIKE LOWER(?)) ORDER BY id DESC LIMIT 10", sql)
+
+	t.Run("validator validates first", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"age"})).ToSQLFor(DialectPostgres)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'name' is not allowed")
+	})
+}
+
+func TestQueryBuilder_SetDistinctOn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("latest row per group", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders").SetDialect(DialectPostgres)
+		qb.SetDistinctOn("user_id")
+		qb.SetFields([]string{"user_id", "total"})
+		qb.SetSort([]string{"user_id", "-created_at"})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT DISTINCT ON (user_id) user_id, total FROM orders ORDER BY user_id ASC, created_at DESC", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("multiple fields with select all", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders").SetDialect(DialectPostgres)
+		qb.SetDistinctOn("user_id", "status")
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT DISTINCT ON (user_id, status) * FROM orders", sql)
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders").SetDialect(DialectPostgres)
+		qb.SetDistinctOn("user_id; DROP TABLE orders")
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid DISTINCT ON field 'user_id; DROP TABLE orders'")
+	})
+
+	t.Run("non-postgres dialects are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		for _, dialect := range []Dialect{DialectGeneric, DialectMySQL, DialectSQLite} {
+			qb := NewQueryBuilder("orders").SetDialect(dialect)
+			qb.SetDistinctOn("user_id")
+
+			_, _, err := qb.ToSQL()
+			require.Error(t, err, dialect)
+			assert.Contains(t, err.Error(), "DISTINCT ON requires the postgres dialect")
+		}
+	})
+
+	t.Run("validator checks fields", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders").SetDialect(DialectPostgres)
+		qb.SetDistinctOn("secret")
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"user_id"})).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'secret' is not allowed")
+	})
+}
+
+func TestQueryBuilder_ToSQLWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		timeout time.Duration
+		setup   string
+		query   string
+		wantErr string
+	}{
+		{
+			name:    "postgres",
+			dialect: DialectPostgres,
+			timeout: 2 * time.Second,
+			setup:   "SET LOCAL statement_timeout = '2000ms'",
+			query:   "SELECT * FROM users WHERE age > $1 LIMIT 10",
+		},
+		{
+			name:    "mysql uses an optimizer hint",
+			dialect: DialectMySQL,
+			timeout: 1500 * time.Millisecond,
+			query:   "SELECT /*+ MAX_EXECUTION_TIME(1500) */ * FROM users WHERE age > ? LIMIT 10",
+		},
+		{
+			name:    "rounds up to a millisecond",
+			dialect: DialectPostgres,
+			timeout: time.Microsecond,
+			setup:   "SET LOCAL statement_timeout = '1ms'",
+			query:   "SELECT * FROM users WHERE age > $1 LIMIT 10",
+		},
+		{
+			name:    "omitted without timeout",
+			dialect: DialectMySQL,
+			query:   "SELECT * FROM users WHERE age > ? LIMIT 10",
+		},
+		{
+			name:    "omitted without timeout on any dialect",
+			dialect: DialectSQLite,
+			query:   "SELECT * FROM users WHERE age > ? LIMIT 10",
+		},
+		{
+			name:    "unsupported dialect",
+			dialect: DialectSQLite,
+			timeout: time.Second,
+			wantErr: "statement timeout requires the postgres or mysql dialect",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter("age>18")
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users").SetDialect(tc.dialect)
+			if tc.dialect == DialectPostgres {
+				qb.SetPlaceholder("$1")
+			}
+			qb.SetFilter(filter)
+			qb.SetLimit(10)
+
+			setup, query, args, err := qb.ToSQLWithTimeout(tc.timeout)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.setup, setup)
+			assert.Equal(t, tc.query, query)
+			assert.Equal(t, []any{18}, args)
+		})
+	}
+
+	t.Run("validator validates first", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("name='jo'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users").SetDialect(DialectPostgres)
+		qb.SetFilter(filter)
+
+		_, _, _, err = qb.Validate(WithAllowedFields([]string{"age"})).ToSQLWithTimeout(time.Second)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'name' is not allowed")
+	})
+}
+
+func TestQueryBuilder_ToCountSQL(t *testing.T) {
+	t.Parallel()
+
+	newQuery := func(t *testing.T) *QueryBuilder {
+		filter, err := parser.ParseFilter("price>10 && active=true")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("products")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"id", "name"})
+		qb.SetSort([]string{"-price"})
+		qb.SetLimit(20)
+		qb.SetOffset(40)
+		return qb
+	}
+
+	t.Run("COUNT(*) ignores fields, sort and pagination", func(t *testing.T) {
+		t.Parallel()
+
+		sql, args, err := newQuery(t).ToCountSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) FROM products WHERE (price > ? AND active = ?)", sql)
+		assert.Equal(t, []any{10, true}, args)
+	})
+
+	t.Run("COUNT(DISTINCT field) with the same filter", func(t *testing.T) {
+		t.Parallel()
+
+		sql, args, err := newQuery(t).ToCountDistinctSQL("category")
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(DISTINCT category) FROM products WHERE (price > ? AND active = ?)", sql)
+		assert.Equal(t, []any{10, true}, args)
+	})
+
+	t.Run("distinct field is qualified with the alias", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("products")
+		qb.SetTableAlias("p")
+
+		sql, _, err := qb.ToCountDistinctSQL("category")
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(DISTINCT p.category) FROM products AS p", sql)
+	})
+
+	t.Run("rejects invalid distinct field", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := NewQueryBuilder("products").ToCountDistinctSQL("category) FROM users --")
+		require.Error(t, err)
+		assert.Equal(t, "invalid count field 'category) FROM users --'", err.Error())
+	})
+}
+
+func TestQueryBuilder_HasMany(t *testing.T) {
+	t.Parallel()
+
+	t.Run("related field condition becomes EXISTS", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("orders.status='paid'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.HasMany("orders", "orders.user_id = users.id")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.status = ?)", sql)
+		assert.Equal(t, []any{"paid"}, args)
+	})
+
+	t.Run("combines with base table conditions in order", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && orders.total>=100 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.HasMany("orders", "orders.user_id = users.id")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > $1 AND EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.total >= $2) AND status = $3)", sql)
+		assert.Equal(t, []any{18, 100, "active"}, args)
+	})
+
+	t.Run("undeclared qualified fields are left as is", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("profiles.verified=true")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.HasMany("orders", "orders.user_id = users.id")
+		qb.SetFilter(filter)
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE profiles.verified = ?", sql)
+	})
+}
+
+func TestQueryBuilder_BracketOperators(t *testing.T) {
+	t.Parallel()
+
+	p, err := parser.NewParser(parser.WithBracketOperators())
+	require.NoError(t, err)
+
+	filter, err := p.ParseFilter("age[gte]:18 && price[lt]:100 && name[like]:'a%' && status[in]:('active','pending')")
+	require.NoError(t, err)
+
+	qb := NewQueryBuilder("users")
+	qb.SetFilter(filter)
+
+	sql, args, err := qb.ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (age >= ? AND price < ? AND name LIKE ? AND status IN (?, ?))", sql)
+	assert.Equal(t, []any{18, 100, "a%", "active", "pending"}, args)
+}
+
+func TestQueryBuilder_ArgCount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   string
+		setup    func(qb *QueryBuilder)
+		expected int
+	}{
+		{
+			name:     "IN arrays count one per value",
+			filter:   "id IN (1,2,3) && status NOT IN ('a','b')",
+			expected: 5,
+		},
+		{
+			name:     "nested groups",
+			filter:   "(age>18 && (role='admin' || tag IN ('x','y'))) || name='bob'",
+			expected: 5,
+		},
+		{
+			name:     "null checks and arithmetic literals bind nothing",
+			filter:   "deleted_at IS NULL && price * 2 > 10",
+			expected: 1,
+		},
+		{
+			name:     "bare predicates only when enabled",
+			filter:   "active && !banned",
+			setup:    func(qb *QueryBuilder) { qb.SetBarePredicates(true) },
+			expected: 2,
+		},
+		{
+			name:   "Postgres array params bind one per list",
+			filter: "id IN (1,2,3)",
+			setup: func(qb *QueryBuilder) {
+				qb.SetDialect(DialectPostgres)
+				qb.SetArrayParams(true)
+			},
+			expected: 1,
+		},
+		{
+			name:   "raw clause and IN-conditions",
+			filter: "age>18",
+			setup: func(qb *QueryBuilder) {
+				qb.SetRawWhere("tenant_id = $1 OR owner_id = $1 OR note = '?'", 7)
+				qb.AddInCondition("team_id", []any{1, 2})
+			},
+			expected: 5,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+			if tc.setup != nil {
+				tc.setup(qb)
+			}
+
+			assert.Equal(t, tc.expected, qb.ArgCount())
+
+			_, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Len(t, args, tc.expected)
+		})
+	}
+
+	t.Run("does not touch args of a previous build", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && id IN (1,2)")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, args, err := qb.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, qb.ArgCount())
+		assert.Equal(t, []any{18, 1, 2}, args)
+	})
+}
+
+func TestQueryBuilder_SetSortCollations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("collated ascending sort", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSortCollations(map[string]string{"name": `"C"`})
+		qb.SetSort([]string{"name"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM users ORDER BY name COLLATE "C" ASC`, sql)
+	})
+
+	t.Run("unconfigured fields omit COLLATE", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetDialect(DialectMySQL)
+		qb.SetSortCollations(map[string]string{"name": "utf8mb4_unicode_ci"})
+		qb.SetSort([]string{"-created_at", "name:desc", "id"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY created_at DESC, name COLLATE utf8mb4_unicode_ci DESC, id ASC", sql)
+	})
+
+	t.Run("qualified with the table alias", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetTableAlias("u")
+		qb.SetSortCollations(map[string]string{"name": `"C"`})
+		qb.SetSort([]string{"name"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM users AS u ORDER BY u.name COLLATE "C" ASC`, sql)
+	})
+}
+
+func TestQueryBuilder_SetSortOrders(t *testing.T) {
+	t.Parallel()
+
+	orders := map[string][]string{"status": {"active", "pending"}}
+
+	t.Run("custom order emits CASE", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSortOrders(orders)
+		qb.SetSort([]string{"status:custom", "id"})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY CASE status WHEN 'active' THEN 0 WHEN 'pending' THEN 1 ELSE 2 END ASC, id ASC", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("descending reverses the order", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSortOrders(orders)
+		qb.SetSort([]string{"-status:custom"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY CASE status WHEN 'active' THEN 0 WHEN 'pending' THEN 1 ELSE 2 END DESC", sql)
+	})
+
+	t.Run("quoted column and escaped values", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetDialect(DialectPostgres)
+		qb.SetQuoteIdentifiers(true)
+		qb.SetSortOrders(map[string][]string{"status": {"won't fix"}})
+		qb.SetSort([]string{"status:CUSTOM"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "users" ORDER BY CASE "status" WHEN 'won''t fix' THEN 0 ELSE 1 END ASC`, sql)
+	})
+
+	t.Run("field without custom order", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSortOrders(orders)
+		qb.SetSort([]string{"name:custom"})
+
+		_, _, err := qb.ToSQL()
+		require.EqualError(t, err, "field 'name' has no custom sort order")
+	})
+
+	t.Run("custom order on a function", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSortOrders(orders)
+		qb.SetSort([]string{"LOWER(status):custom"})
+
+		_, _, err := qb.ToSQL()
+		require.EqualError(t, err, "invalid sort expression 'LOWER(status):custom': custom order applies to a field")
+	})
+}
+
+func TestQueryBuilder_Ranges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		filter       string
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:         "integer range",
+			filter:       "age=18..65",
+			expectedSQL:  "SELECT * FROM users WHERE age BETWEEN ? AND ?",
+			expectedArgs: []any{18, 65},
+		},
+		{
+			name:         "float range",
+			filter:       "score=0.5..2.5 && active=true",
+			expectedSQL:  "SELECT * FROM users WHERE (score BETWEEN ? AND ? AND active = ?)",
+			expectedArgs: []any{0.5, 2.5, true},
+		},
+		{
+			name:         "negated range",
+			filter:       "age!=18..65",
+			expectedSQL:  "SELECT * FROM users WHERE age NOT BETWEEN ? AND ?",
+			expectedArgs: []any{18, 65},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+			assert.Equal(t, len(tc.expectedArgs), qb.ArgCount())
+		})
+	}
+
+	t.Run("other operators fail", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18..65")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.ToSQL()
+		require.Error(t, err)
+		assert.Equal(t, "operator > on field 'age' cannot compare with a range", err.Error())
+	})
+}
+
+func TestQueryBuilder_SetCaseFold(t *testing.T) {
+	t.Parallel()
+
+	t.Run("case-folded field compares lowercased values", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("email='Bob@Example.com' && username!='Admin'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetCaseFold("email", "username")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (LOWER(email) = LOWER(?) AND LOWER(username) != LOWER(?))", sql)
+		assert.Equal(t, []any{"bob@example.com", "admin"}, args)
+	})
+
+	t.Run("other fields stay plain", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("email='Bob@Example.com' && name='Bob'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetCaseFold("email")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (LOWER(email) = LOWER(?) AND name = ?)", sql)
+		assert.Equal(t, []any{"bob@example.com", "Bob"}, args)
+	})
+}
+
+func TestQueryBuilder_SetJSONColumns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		filter   string
+		expected string
+		args     []any
+	}{
+		{
+			name:     "Postgres",
+			dialect:  DialectPostgres,
+			filter:   "data.country='US'",
+			expected: "SELECT * FROM users WHERE data->>'country' = $1",
+			args:     []any{"US"},
+		},
+		{
+			name:     "Postgres nested path",
+			dialect:  DialectPostgres,
+			filter:   "data.address.city='Lisbon'",
+			expected: "SELECT * FROM users WHERE data->'address'->>'city' = $1",
+			args:     []any{"Lisbon"},
+		},
+		{
+			name:     "MySQL",
+			dialect:  DialectMySQL,
+			filter:   "data.country='US'",
+			expected: "SELECT * FROM users WHERE JSON_EXTRACT(data, '$.country') = $1",
+			args:     []any{"US"},
+		},
+		{
+			name:     "MySQL nested path",
+			dialect:  DialectMySQL,
+			filter:   "data.address.city='Lisbon'",
+			expected: "SELECT * FROM users WHERE JSON_EXTRACT(data, '$.address.city') = $1",
+			args:     []any{"Lisbon"},
+		},
+		{
+			name:     "undeclared columns stay qualified fields",
+			dialect:  DialectPostgres,
+			filter:   "profiles.country='US'",
+			expected: "SELECT * FROM users WHERE profiles.country = $1",
+			args:     []any{"US"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetPlaceholder("$1")
+			qb.SetDialect(tc.dialect)
+			qb.SetJSONColumns("data")
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+
+	t.Run("sort by JSON path", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetDialect(DialectPostgres)
+		qb.SetJSONColumns("data")
+		qb.SetSort([]string{"-data.country"})
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY data->>'country' DESC", sql)
+	})
+}
+
+func TestQueryBuilder_SetFoldOrEquals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected string
+		args     []any
+	}{
+		{
+			name:     "equalities on one field",
+			filter:   "status='a' || status='b'",
+			expected: "SELECT * FROM users WHERE status IN (?, ?)",
+			args:     []any{"a", "b"},
+		},
+		{
+			name:     "IN with an equality",
+			filter:   "status IN ('a','b') || status='c'",
+			expected: "SELECT * FROM users WHERE status IN (?, ?, ?)",
+			args:     []any{"a", "b", "c"},
+		},
+		{
+			name:     "repeated values are bound once",
+			filter:   "id=1 || id IN (1,2) || id=2",
+			expected: "SELECT * FROM users WHERE id IN (?, ?)",
+			args:     []any{1, 2},
+		},
+		{
+			name:     "mixed fields are left untouched",
+			filter:   "status='a' || role='admin'",
+			expected: "SELECT * FROM users WHERE (status = ? OR role = ?)",
+			args:     []any{"a", "admin"},
+		},
+		{
+			name:     "other branches keep their place",
+			filter:   "status='a' || role='admin' || status='b'",
+			expected: "SELECT * FROM users WHERE (status IN (?, ?) OR role = ?)",
+			args:     []any{"a", "b", "admin"},
+		},
+		{
+			name:     "AND groups are not folded",
+			filter:   "(status='a' && age>18) || status='b'",
+			expected: "SELECT * FROM users WHERE ((status = ? AND age > ?) OR status = ?)",
+			args:     []any{"a", 18, "b"},
+		},
+		{
+			name:     "nested groups are folded",
+			filter:   "age>18 && (role='admin' || role='owner')",
+			expected: "SELECT * FROM users WHERE (age > ? AND role IN (?, ?))",
+			args:     []any{18, "admin", "owner"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFoldOrEquals(true)
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+			assert.Equal(t, tc.args, args)
+			assert.Equal(t, len(tc.args), qb.ArgCount())
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("status='a' || status='b'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (status = ? OR status = ?)", sql)
+	})
+
+	t.Run("case-folded fields are not folded", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("email='A' || email='B'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFoldOrEquals(true)
+		qb.SetCaseFold("email")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (LOWER(email) = LOWER(?) OR LOWER(email) = LOWER(?))", sql)
+		assert.Equal(t, []any{"a", "b"}, args)
+		assert.Equal(t, 2, qb.ArgCount())
+	})
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		limit    int
+		offset   int
+		total    int
+		expected PaginationMeta
+	}{
+		{
+			name:     "first page",
+			limit:    10,
+			total:    35,
+			expected: PaginationMeta{Page: 1, PerPage: 10, Total: 35, TotalPages: 4, HasNext: true},
+		},
+		{
+			name:     "middle page",
+			limit:    10,
+			offset:   20,
+			total:    35,
+			expected: PaginationMeta{Page: 3, PerPage: 10, Total: 35, TotalPages: 4, HasNext: true, HasPrev: true},
+		},
+		{
+			name:     "last page",
+			limit:    10,
+			offset:   30,
+			total:    35,
+			expected: PaginationMeta{Page: 4, PerPage: 10, Total: 35, TotalPages: 4, HasPrev: true},
+		},
+		{
+			name:     "exact multiple",
+			limit:    10,
+			offset:   10,
+			total:    20,
+			expected: PaginationMeta{Page: 2, PerPage: 10, Total: 20, TotalPages: 2, HasPrev: true},
+		},
+		{
+			name:     "no rows",
+			limit:    10,
+			expected: PaginationMeta{Page: 1, PerPage: 10},
+		},
+		{
+			name:     "no limit",
+			total:    7,
+			expected: PaginationMeta{Page: 1, PerPage: 7, Total: 7, TotalPages: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("users")
+			qb.SetLimit(tc.limit)
+			qb.SetOffset(tc.offset)
+
+			assert.Equal(t, tc.expected, Paginate(qb, tc.total))
+			assert.Equal(t, tc.expected, Paginate(qb.Validate(WithMaxLimit(100)), tc.total))
+		})
+	}
+}
+
+func TestQueryBuilder_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fields   []string
+		group    []string
+		expected string
+	}{
+		{"count with alias", []string{"category", "COUNT(id) AS total"}, []string{"category"}, "SELECT category, COUNT(id) AS total FROM products GROUP BY category"},
+		{"count star", []string{"category", "count(*)"}, []string{"category"}, "SELECT category, COUNT(*) FROM products GROUP BY category"},
+		{"several aggregates", []string{"category", "sum(price) as revenue", "MAX(price)"}, []string{"category"}, "SELECT category, SUM(price) AS revenue, MAX(price) FROM products GROUP BY category"},
+		{"several group fields", []string{"category", "brand", "AVG(price) AS avg_price"}, []string{"category", "brand"}, "SELECT category, brand, AVG(price) AS avg_price FROM products GROUP BY category, brand"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("products")
+			qb.SetFields(tc.fields)
+			qb.SetGroupBy(tc.group)
+
+			sql, _, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+		})
+	}
+
+	t.Run("group by precedes order by", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("price > 10")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("products")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"category", "COUNT(id) AS total"})
+		qb.SetGroupBy([]string{"category"})
+		qb.SetSort([]string{"category"})
+		qb.SetLimit(10)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT category, COUNT(id) AS total FROM products WHERE price > ? GROUP BY category ORDER BY category ASC LIMIT 10", sql)
+		assert.Equal(t, []any{10}, args)
+	})
+
+	invalid := []struct {
+		name  string
+		field string
+		err   string
+	}{
+		{"unknown function", "LOWER(name)", "function 'LOWER' is not allowed in fields"},
+		{"star outside count", "SUM(*)", "invalid field expression 'SUM(*)'"},
+		{"expression argument", "COUNT(id + 1)", "invalid field expression 'COUNT(id + 1)'"},
+		{"invalid alias", "COUNT(id) AS x; DROP", "invalid field expression 'COUNT(id) AS x; DROP'"},
+		{"missing AS", "COUNT(id) total", "invalid field expression 'COUNT(id) total'"},
+	}
+
+	for _, tc := range invalid {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("products")
+			qb.SetFields([]string{tc.field})
+
+			_, _, err := qb.ToSQL()
+			require.Error(t, err)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+
+	t.Run("only the aggregated field is validated", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("products")
+		qb.SetFields([]string{"Category", "COUNT(ID) AS total", "COUNT(*)"})
+		qb.SetGroupBy([]string{"CATEGORY"})
+
+		sql, _, err := qb.Validate(
+			WithAllowedFields([]string{"category", "id"}),
+			WithCaseInsensitiveFields(),
+		).ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT category, COUNT(id) AS total, COUNT(*) FROM products GROUP BY category", sql)
+	})
+
+	t.Run("disallowed fields are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("products")
+		qb.SetFields([]string{"category", "SUM(cost)"})
+		qb.SetGroupBy([]string{"supplier"})
+
+		errs := qb.Validate(WithAllowedFields([]string{"category", "id"})).Validate()
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "field 'cost' is not allowed")
+		assert.Contains(t, errs[1].Error(), "field 'supplier' is not allowed")
+	})
+}
+
+func TestQueryBuilder_SetGroupByRollup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		rollup   bool
+		expected string
+	}{
+		{"plain group by", DialectGeneric, false, "SELECT category, status, COUNT(id) AS total FROM orders GROUP BY category, status"},
+		{"rollup", DialectGeneric, true, "SELECT category, status, COUNT(id) AS total FROM orders GROUP BY ROLLUP(category, status)"},
+		{"rollup on postgres", DialectPostgres, true, "SELECT category, status, COUNT(id) AS total FROM orders GROUP BY ROLLUP(category, status)"},
+		{"rollup on mysql", DialectMySQL, true, "SELECT category, status, COUNT(id) AS total FROM orders GROUP BY category, status WITH ROLLUP"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("orders")
+			qb.SetDialect(tc.dialect)
+			qb.SetFields([]string{"category", "status", "COUNT(id) AS total"})
+			if tc.rollup {
+				qb.SetGroupByRollup([]string{"category", "status"})
+			} else {
+				qb.SetGroupBy([]string{"category", "status"})
+			}
+
+			sql, _, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+		})
+	}
+
+	t.Run("rollup is rejected on sqlite", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetDialect(DialectSQLite)
+		qb.SetGroupByRollup([]string{"category"})
+
+		_, _, err := qb.ToSQL()
+		require.EqualError(t, err, "GROUP BY ROLLUP is not supported by the sqlite dialect")
+	})
+
+	t.Run("rollup fields are validated", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("orders")
+		qb.SetGroupByRollup([]string{"category", "supplier"})
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"category", "status"})).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'supplier' is not allowed")
+	})
+}
+
+func TestQueryBuilder_SetNullSafeNotIn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		filter       string
+		nullSafe     bool
+		arrayParams  bool
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:         "default keeps standard NOT IN",
+			filter:       "status NOT IN ('a', 'b')",
+			expectedSQL:  "SELECT * FROM users WHERE status NOT IN ($1, $2)",
+			expectedArgs: []any{"a", "b"},
+		},
+		{
+			name:         "NOT IN keeps NULL rows",
+			filter:       "status NOT IN ('a', 'b')",
+			nullSafe:     true,
+			expectedSQL:  "SELECT * FROM users WHERE (status NOT IN ($1, $2) OR status IS NULL)",
+			expectedArgs: []any{"a", "b"},
+		},
+		{
+			name:         "IN is unchanged",
+			filter:       "status IN ('a', 'b')",
+			nullSafe:     true,
+			expectedSQL:  "SELECT * FROM users WHERE status IN ($1, $2)",
+			expectedArgs: []any{"a", "b"},
+		},
+		{
+			name:         "explicit null is left as written",
+			filter:       "status NOT IN ('a', null)",
+			nullSafe:     true,
+			expectedSQL:  "SELECT * FROM users WHERE (status NOT IN ($1) AND status IS NOT NULL)",
+			expectedArgs: []any{"a"},
+		},
+		{
+			name:         "array param",
+			filter:       "id NOT IN (1, 2)",
+			nullSafe:     true,
+			arrayParams:  true,
+			expectedSQL:  "SELECT * FROM users WHERE (id != ALL($1) OR id IS NULL)",
+			expectedArgs: []any{[]any{1, 2}},
+		},
+		{
+			name:         "inside an AND chain",
+			filter:       "age > 18 && status NOT IN ('a')",
+			nullSafe:     true,
+			expectedSQL:  "SELECT * FROM users WHERE (age > $1 AND (status NOT IN ($2) OR status IS NULL))",
+			expectedArgs: []any{18, "a"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetPlaceholder("$1")
+			qb.SetDialect(DialectPostgres)
+			qb.SetArrayParams(tc.arrayParams)
+			qb.SetNullSafeNotIn(tc.nullSafe)
+			qb.SetFilter(filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestQueryBuilder_NilNodes(t *testing.T) {
+	t.Parallel()
+
+	str := "'x'"
+	field := &parser.Primary{Field: "a"}
+	single := func(comp *parser.Comparison) *parser.Filter {
+		return &parser.Filter{Expression: &parser.OrExpr{And: []*parser.AndExpr{{Comparison: []*parser.Comparison{comp}}}}}
+	}
+	eq := &parser.Comparison{Left: field, Op: &parser.Operator{Equal: true}, Right: &parser.Value{String: &str}}
+
+	tests := []struct {
+		name         string
+		filter       *parser.Filter
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name: "nil IN element is treated as null",
+			filter: single(&parser.Comparison{
+				Left:  field,
+				Op:    &parser.Operator{In: true},
+				Right: &parser.Value{Array: &parser.Array{Values: []*parser.Value{{String: &str}, nil}}},
+			}),
+			expectedSQL:  "SELECT * FROM users WHERE (a IN (?) OR a IS NULL)",
+			expectedArgs: []any{"x"},
+		},
+		{
+			name: "nil arithmetic is skipped",
+			filter: single(&parser.Comparison{
+				Left:  &parser.Primary{Field: "a", Arith: []*parser.Arithmetic{nil}},
+				Op:    &parser.Operator{Equal: true},
+				Right: &parser.Value{String: &str},
+			}),
+			expectedSQL:  "SELECT * FROM users WHERE a = ?",
+			expectedArgs: []any{"x"},
+		},
+		{
+			name: "nil AND group at a mixed level is skipped",
+			filter: &parser.Filter{Expression: &parser.OrExpr{And: []*parser.AndExpr{
+				nil,
+				{Comparison: []*parser.Comparison{eq, eq}},
+				{Comparison: []*parser.Comparison{eq}},
+			}}},
+			expectedSQL:  "SELECT * FROM users WHERE ((a = ? AND a = ?) OR a = ?)",
+			expectedArgs: []any{"x", "x", "x"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(tc.filter)
+
+			sql, args, err := qb.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+// fuzzFilterSeeds are the filters used in the parser and builder tests, so
+// fuzzing starts from inputs that reach every part of the grammar.
+var fuzzFilterSeeds = []string{
+	"  \tage>18 && status='active'\n ",
+	" \t\n",
+	"!active && verified",
+	"!active",
+	"!age>18",
+	"",
+	"((age>18))",
+	"(age > 18 and active = true) || role = 'admin'",
+	"(age>18 && (role='admin' || tag IN ('x','y'))) || name='bob'",
+	"(age>18 && password='secret') || role='admin'",
+	"(age>18 && status='active') || (role='admin' && verified=true)",
+	"(age>18 && status='active') || role IN ('admin', 'owner')",
+	"(age>18 && status='active') || role='admin'",
+	"(age>18 || vip=true) && status!='banned'",
+	"(age>18)",
+	"(age>=18 && age<=65) && (status='active' || status='pending') && deleted_at IS NULL",
+	"(age>=18 && country='US') || (age>=21 && country='UK')",
+	"(password='x' && token='y') || (name='bob' && (secret=1 || role='admin'))",
+	"(status='a' && age>18) || status='b'",
+	"(status='active' || status='trial') && !(age<18 || banned=true)",
+	"NAME LIKE 'x%'",
+	"SSN='1'",
+	"STATUS='bogus'",
+	"Status='active'",
+	"\ufeff  age>18 ",
+	"\ufeffage>18",
+	"a - 1 > 0",
+	"a -1 > 0",
+	"a = -1",
+	"a IN (-1, -2)",
+	"a IS DISTINCT FROM -1",
+	"a-1 > 0",
+	"a=-5..-1",
+	"a=1 && (b=2 || (c=3 && (d=4 || e=5)))",
+	"a=1 && (b=2 || c=3) && d=4",
+	"a=1 && b=2 && c=3",
+	"a=1 && b=2 || c=3",
+	"a=1 || b=2 || c=3",
+	"a>-1",
+	"active && !banned",
+	"active && age>18",
+	"active",
+	"active=TRUE",
+	"active=true && age>18",
+	"active=true && deleted=false",
+	"active=true",
+	"age   >   18",
+	"age > 18 && status NOT IN ('a')",
+	"age > 18 AND status = 'active' or role = 'admin'",
+	"age > 18 AND status = 'active'",
+	"age > null",
+	"age >= 18 && (role = 'admin' || name ILIKE 'a%')",
+	"age >= 18",
+	"age!=18..65",
+	"age=18",
+	"age=18..",
+	"age=18..65",
+	"age>18 && (role='admin' || role='owner')",
+	"age>18 && (status='active' || status='deleted')",
+	"age>18 && id IN (1,2)",
+	"age>18 && id IN (1,2,3)",
+	"age>18 && name ILIKE 'jo%'",
+	"age>18 && name LIKE 'J%'",
+	"age>18 && name='J'",
+	"age>18 && name='john' && active=true && score>=4.5",
+	"age>18 && orders.total>=100 && status='active'",
+	"age>18 && password='secret'",
+	"age>18 && password='x' && status='active'",
+	"age>18 && password='x'",
+	"age>18 && passwordHash='x'",
+	"age>18 && price * quantity > 100",
+	"age>18 && score<=9.5",
+	"age>18 && ssn='123'",
+	"age>18 && status IN ('a','b')",
+	"age>18 && status IN ('active', 'pending')",
+	"age>18 && status IN ('active', 'trial')",
+	"age>18 && status IN ('active','pending')",
+	"age>18 && status='active'",
+	"age>18 || id NOT IN (1,2,3) || name='x'",
+	"age>18 || role='admin'",
+	"age>18 || status='active'",
+	"age>18",
+	"age>18..65",
+	"age>=18 && age<65",
+	"age[between]:1",
+	"age[gte:18",
+	"age[gte]18",
+	"age[gte]:18 && price[lt]:100 && name[like]:'a%' && status[in]:('active','pending')",
+	"age[gte]:18",
+	"age\t>\t18",
+	"balance<-100",
+	"balance=0.0",
+	"count=0",
+	"count=42",
+	"createdAt>=2024-01-01 && userID=7",
+	"created_at<2024-06-30T23:59:59Z",
+	"created_at>'2024-01-01'",
+	"created_at>2024-01-01",
+	"created_at>2024-13-45",
+	"created_at>=2024-01-01 && created_at<2024-02-01T00:00:00.000+00:00",
+	"created_at>=2024-01-01 && created_at<2024-02-01T00:00:00Z",
+	"created_at>=2024-01-01",
+	"created_at>=2024-01-01T10:00:00Z",
+	"created_at>=2024-01-01T10:30:00-03:00",
+	"data.address.city='Lisbon'",
+	"data.country='US'",
+	"deleted=FALSE",
+	"deleted=false",
+	"deleted_at IS DISTINCT FROM null && manager_id IS NOT DISTINCT FROM null",
+	"deleted_at IS NULL && price * 2 > 10",
+	"deleted_at IS NULL && status='active'",
+	"deleted_at IS NULL && updated_at IS NOT NULL",
+	"deleted_at IS NULL",
+	"deleted_at is null",
+	"delta=-5..-1 && created_at=2024-01-01..2024-12-31",
+	"email != null",
+	"email IS NOT NULL",
+	"email NOT ILIKE '%.test'",
+	"email NOT ILIKE '%test%'",
+	"email NOT REGEXP '@test'",
+	"email REGEXP '@example'",
+	"email is not null",
+	"email not ilike '%test%'",
+	"email not regexp '@test'",
+	"email='A' || email='B'",
+	"email='Bob@Example.com' && name='Bob'",
+	"email='Bob@Example.com' && username!='Admin'",
+	"email='user@example.com'",
+	"email=\"test@example.com\"",
+	"event_type='click' && user_id IN (1,2)",
+	"field123=456",
+	"first-name = 'john'",
+	"firstName='john' && createdAt>2024-01-01",
+	"firstName='john' && price*taxRate>10",
+	"first_name='john'",
+	"id % 2 = 0",
+	"id IN (1, 2, 3, 5, 8)",
+	"id IN (1, null, 2)",
+	"id IN (1,2)",
+	"id IN (1,2,3) && status NOT IN ('a','b')",
+	"id IN (1,2,3)",
+	"id NOT IN (1, 2)",
+	"id NOT IN (1,2)",
+	"id NOT IN(-1,-2)",
+	"id=1 && (name='a' || name ILIKE 'b%')",
+	"id=1 || id IN (1,2) || id=2",
+	"id=1",
+	"manager_id <=> 5",
+	"manager_id <=> null",
+	"manager_id is not distinct from 5",
+	"name = '[gte]:x'",
+	"name ILIKE '%john%'",
+	"name ILIKE 'a%'",
+	"name ILIKE 'j_hn%'",
+	"name IN ('a','b') && password='x'",
+	"name LIKE '%John_Doe%'",
+	"name LIKE '%john%'",
+	"name LIKE 'J_hn%'",
+	"name LIKE 'a%' && age > 18 && age <> 30",
+	"name LIKE 'jo%' || name ILIKE 'JO%'",
+	"name NOT LIKE '%test%'",
+	"name ilike '%john%'",
+	"name like '%john%'",
+	"name not like '%test%'",
+	"name!~'John'",
+	"name=''",
+	"name='John Doe'",
+	"name='jo'",
+	"name='john'",
+	"name='x' && deleted_at IS NOT NULL",
+	"name=\"O'Brien\"",
+	"name~'John%'",
+	"name~'John'",
+	"orders.status='paid'",
+	"p.verified=true && age>18",
+	"password='secret'",
+	"password='x' || token='y'",
+	"password='x'",
+	"path='C:\\tmp'",
+	"price * 2 + tax >= 100",
+	"price * > 100",
+	"price * cost > 1000",
+	"price * quantity > 100",
+	"price * quantity > 1000",
+	"price < 9.5",
+	"price > 10",
+	"price*2-1.5 > 0",
+	"price*qty>100",
+	"price<100",
+	"price=1.5..9.99",
+	"price=19.99",
+	"price>10 && active=true",
+	"profiles.country='US'",
+	"profiles.ssn='123'",
+	"profiles.verified=true && users.age>18",
+	"profiles.verified=true",
+	"rating>=4.5",
+	"role NOT IN ('admin', 'superadmin')",
+	"role not in ('admin', 'superadmin')",
+	"score IN (-1, -2.5, 3)",
+	"score=0.5..2.5 && active=true",
+	"score>=-1.5",
+	"secret=1 && name='bob'",
+	"secrets.token='x'",
+	"status != NULL",
+	"status = null",
+	"status IN ('a', 'b')",
+	"status IN ('a','active','b')",
+	"status IN ('a','b') && age>18",
+	"status IN ('a','b') && id=1",
+	"status IN ('a','b') || status='c'",
+	"status IN ('a','b')",
+	"status IN ('a','b','c') && id NOT IN (1,2)",
+	"status IN ('a',null) && age=18..65",
+	"status IN ('active')",
+	"status IN ('active', 'pending')",
+	"status IN ('active', 'pending', 'approved')",
+	"status IN ('active', 'pending', 'trial')",
+	"status IN ('active', null) || status IS NULL",
+	"status IN ('active', null)",
+	"status IN ('active', null, NULL)",
+	"status IN ('active','pending') && id NOT IN (1,2)",
+	"status IN ('active','pending')",
+	"status IN (null)",
+	"status IS DISTINCT FROM 'active'",
+	"status LIKE 'act%'",
+	"status NOT IN ('a', 'b')",
+	"status NOT IN ('a', null)",
+	"status NOT IN ('active', 'pending', NULL)",
+	"status NOT IN ('active','archivd')",
+	"status NOT IN (null)",
+	"status in ('active', 'pending')",
+	"status!='active'",
+	"status!='inactive'",
+	"status<>'banned'",
+	"status<>'inactive'",
+	"status='a' && (age >= 18 || name='bob')",
+	"status='a' || role='admin' || status='b'",
+	"status='a' || role='admin'",
+	"status='a' || status='b'",
+	"status='active' && age>18",
+	"status='active'",
+	"status='bogus'",
+	"status=1",
+	"stock<=10",
+	"tags CONTAINS 'go'",
+	"tags CONTAINS ('go', 'sql') && score>3",
+	"tags contains ('go', 'sql')",
+	"temperature>-3.5",
+	"tenant_id=7",
+	"u.age>18 && status='active'",
+	"u.password='x'",
+	"user_id=123",
+	"users.name LIKE 'x%'",
+	"users.ssn='1'",
+	"users.status='bogus'",
+	"value IN (1, 2.5, 3)",
+}
+
+func FuzzParseFilter(f *testing.F) {
+	for _, seed := range fuzzFilterSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		filter, err := parser.ParseFilter(input)
+		if err != nil {
+			return
+		}
+
+		for _, dialect := range []Dialect{DialectGeneric, DialectPostgres, DialectMySQL} {
+			qb := NewQueryBuilder("users")
+			qb.SetDialect(dialect)
+			qb.SetBarePredicates(true)
+			qb.SetFoldOrEquals(true)
+			qb.SetFilter(filter)
+			_, _, _ = qb.ToSQL()
+			_, _ = qb.ToSQLInline()
+			_, _, _ = qb.Validate(WithAllowedFields([]string{"age", "status"})).ToSQL()
+		}
+	})
 }
@@ -0,0 +1,148 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Cursor is the pagination state carried by an opaque cursor token, so
+// clients page through results without seeing raw offsets. A cursor either
+// holds an offset or, for keyset pagination, the sort values of the last row
+// returned.
+type Cursor struct {
+	Limit  int   `json:"limit,omitempty"`
+	Offset int   `json:"offset,omitempty"`
+	After  []any `json:"after,omitempty"` // Sort values of the last row, in sort order
+}
+
+// EncodeCursor encodes a cursor as a URL-safe base64 token.
+func EncodeCursor(c Cursor) string {
+	// Marshalling a Cursor cannot fail for the JSON values After holds
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a token produced by EncodeCursor. Whole numbers in
+// After are decoded as int and other numbers as float64.
+func DecodeCursor(token string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var c Cursor
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.Limit < 0 || c.Offset < 0 {
+		return Cursor{}, errors.New("invalid cursor: negative limit or offset")
+	}
+
+	for i, value := range c.After {
+		if n, ok := value.(json.Number); ok {
+			c.After[i] = numberValue(n)
+		}
+	}
+	return c, nil
+}
+
+// numberValue converts a JSON number into an int when it is whole.
+func numberValue(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return int(i)
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// SetCursor applies a decoded cursor: its limit, when set, and either its
+// keyset values or its offset. Keyset values add a predicate selecting the
+// rows after them in sort order, e.g. "(created_at < ? OR (created_at = ? AND
+// id > ?))" for sort "-created_at,id", and need one value per sort field.
+func (qb *QueryBuilder) SetCursor(c Cursor) *QueryBuilder {
+	if c.Limit > 0 {
+		qb.limit = c.Limit
+	}
+	qb.after = c.After
+	if len(c.After) > 0 {
+		qb.offset = 0
+	} else {
+		qb.offset = c.Offset
+	}
+	return qb
+}
+
+// buildKeyset builds the predicate selecting rows after the cursor's keyset
+// values in sort order.
+func (qb *QueryBuilder) buildKeyset() string {
+	sort := qb.orderBy()
+	if len(sort) != len(qb.after) {
+		qb.fail(fmt.Errorf("cursor has %d keyset values for %d sort fields", len(qb.after), len(sort)))
+		return ""
+	}
+
+	exprs := make([]string, 0, len(sort))
+	ops := make([]string, 0, len(sort))
+	for _, s := range sort {
+		expr, err := parseSort(s)
+		if err != nil {
+			qb.fail(err)
+			return ""
+		}
+		if expr.position > 0 {
+			qb.fail(fmt.Errorf("cursor cannot follow sort position %d", expr.position))
+			return ""
+		}
+		if expr.custom {
+			qb.fail(fmt.Errorf("cursor cannot follow custom sort order on field '%s'", expr.fields[0]))
+			return ""
+		}
+		for j, field := range expr.fields {
+			expr.fields[j] = qb.column(field)
+		}
+		exprs = append(exprs, expr.expr())
+		if expr.desc {
+			ops = append(ops, " < ")
+		} else {
+			ops = append(ops, " > ")
+		}
+	}
+
+	// Each term matches rows equal on the preceding sort fields and after the
+	// cursor on the next one
+	terms := make([]string, 0, len(exprs))
+	for i := range exprs {
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, qb.keysetCondition(exprs[j], " = ", qb.after[j]))
+		}
+		conds = append(conds, qb.keysetCondition(exprs[i], ops[i], qb.after[i]))
+		if len(conds) == 1 {
+			terms = append(terms, conds[0])
+		} else {
+			terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+// keysetCondition binds value and compares expr with it.
+func (qb *QueryBuilder) keysetCondition(expr, op string, value any) string {
+	qb.addArg(value, kindOf(value))
+	return expr + op + qb.getPlaceholder()
+}
+
+// countKeyset returns the number of arguments the keyset predicate binds.
+func (qb *QueryBuilder) countKeyset() int {
+	n := len(qb.after)
+	return n * (n + 1) / 2
+}
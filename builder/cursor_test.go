@@ -0,0 +1,136 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"offset", Cursor{Limit: 20, Offset: 40}},
+		{"keyset", Cursor{Limit: 20, After: []any{"2024-01-15T10:00:00Z", 42, 9.5, true}}},
+		{"empty", Cursor{}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := EncodeCursor(tc.cursor)
+			assert.NotContains(t, token, "=")
+
+			cursor, err := DecodeCursor(token)
+			require.NoError(t, err)
+			assert.Equal(t, tc.cursor, cursor)
+		})
+	}
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeCursor("not a cursor!")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid cursor")
+	})
+
+	t.Run("negative offset", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeCursor(EncodeCursor(Cursor{Offset: -5}))
+		require.Error(t, err)
+		assert.Equal(t, "invalid cursor: negative limit or offset", err.Error())
+	})
+}
+
+func TestQueryBuilder_SetCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("offset cursor", func(t *testing.T) {
+		t.Parallel()
+
+		cursor, err := DecodeCursor(EncodeCursor(Cursor{Limit: 20, Offset: 40}))
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"id"})
+		qb.SetCursor(cursor)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY id ASC LIMIT 20 OFFSET 40", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("keyset cursor", func(t *testing.T) {
+		t.Parallel()
+
+		cursor, err := DecodeCursor(EncodeCursor(Cursor{Limit: 10, After: []any{"2024-01-15", 42}}))
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetPlaceholder("$1")
+		qb.SetSort([]string{"-created_at"})
+		qb.SetDefaultSort("id")
+		qb.SetOffset(100)
+		qb.SetCursor(cursor)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (created_at < $1 OR (created_at = $2 AND id > $3)) ORDER BY created_at DESC, id ASC LIMIT 10", sql)
+		assert.Equal(t, []any{"2024-01-15", "2024-01-15", 42}, args)
+		assert.Equal(t, len(args), qb.ArgCount())
+	})
+
+	t.Run("keyset cursor with filter", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.AddInCondition("status", []any{"active"})
+		qb.SetSort([]string{"id"})
+		qb.SetCursor(Cursor{After: []any{7}})
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (status IN (?) AND id > ?) ORDER BY id ASC", sql)
+		assert.Equal(t, []any{"active", 7}, args)
+	})
+
+	t.Run("count ignores the keyset cursor", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.AddInCondition("status", []any{"active"})
+		qb.SetSort([]string{"id"})
+		qb.SetCursor(Cursor{Limit: 10, After: []any{7}})
+
+		sql, args, err := qb.ToCountSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(*) FROM users WHERE status IN (?)", sql)
+		assert.Equal(t, []any{"active"}, args)
+
+		sql, args, err = qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (status IN (?) AND id > ?) ORDER BY id ASC LIMIT 10", sql)
+		assert.Equal(t, []any{"active", 7}, args)
+	})
+
+	t.Run("keyset values must match the sort", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-created_at", "id"})
+		qb.SetCursor(Cursor{After: []any{42}})
+
+		_, _, err := qb.ToSQL()
+		require.Error(t, err)
+		assert.Equal(t, "cursor has 1 keyset values for 2 sort fields", err.Error())
+	})
+}
@@ -0,0 +1,146 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Dialect identifies the SQL dialect a query is built for.
+// It controls dialect-specific operator emission (e.g. ILIKE on Postgres).
+type Dialect string
+
+const (
+	// DialectGeneric emits portable SQL. This is the default.
+	DialectGeneric Dialect = ""
+	// DialectPostgres emits PostgreSQL-specific SQL.
+	DialectPostgres Dialect = "postgres"
+	// DialectMySQL emits MySQL-specific SQL.
+	DialectMySQL Dialect = "mysql"
+	// DialectSQLite emits SQLite-specific SQL.
+	DialectSQLite Dialect = "sqlite"
+	// DialectOracle emits Oracle-specific SQL.
+	DialectOracle Dialect = "oracle"
+	// DialectClickHouse emits ClickHouse-specific SQL.
+	DialectClickHouse Dialect = "clickhouse"
+	// DialectSQLServer emits SQL Server-specific SQL.
+	DialectSQLServer Dialect = "sqlserver"
+)
+
+// Placeholder returns the dialect's native placeholder style: "$1" for
+// Postgres, "@p1" for SQL Server, ":1" for Oracle and "?" otherwise.
+func (d Dialect) Placeholder() string {
+	switch d {
+	case DialectPostgres:
+		return "$1"
+	case DialectSQLServer:
+		return "@p1"
+	case DialectOracle:
+		return ":1"
+	default:
+		return "?"
+	}
+}
+
+// QuotesIdentifiers reports whether identifiers are quoted by default for
+// the dialect. Oracle and ClickHouse are excluded because quoting makes
+// their identifiers case-sensitive in ways that differ from unquoted names.
+func (d Dialect) QuotesIdentifiers() bool {
+	switch d {
+	case DialectPostgres, DialectMySQL, DialectSQLite, DialectSQLServer:
+		return true
+	default:
+		return false
+	}
+}
+
+// intBooleans reports whether the dialect stores booleans as 1/0 integers.
+func (d Dialect) intBooleans() bool {
+	return d == DialectMySQL || d == DialectSQLite || d == DialectSQLServer
+}
+
+// backslashEscapes reports whether the dialect treats backslashes in string
+// literals as escape characters.
+func (d Dialect) backslashEscapes() bool {
+	return d == DialectMySQL || d == DialectClickHouse
+}
+
+// quoteChar returns the character used to quote identifiers: backticks for
+// MySQL and ClickHouse, double quotes otherwise.
+func (d Dialect) quoteChar() string {
+	if d == DialectMySQL || d == DialectClickHouse {
+		return "`"
+	}
+	return `"`
+}
+
+// quote quotes each part of a (possibly qualified) identifier, doubling any
+// embedded quote characters. SQL Server uses brackets, e.g. [users].[id].
+func (d Dialect) quote(ident string) string {
+	open, q := d.quoteChar(), d.quoteChar()
+	if d == DialectSQLServer {
+		open, q = "[", "]"
+	}
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		parts[i] = open + strings.ReplaceAll(part, q, q+q) + q
+	}
+	return strings.Join(parts, ".")
+}
+
+// jsonExtract returns the expression extracting a path from a JSON column as
+// text: column->'a'->>'b' for Postgres, JSON_VALUE(column, '$.a.b') for
+// Oracle and SQL Server and JSON_EXTRACT(column, '$.a.b') otherwise. Path keys are
+// identifiers, so they are written inline.
+func (d Dialect) jsonExtract(column string, path []string) string {
+	switch d {
+	case DialectPostgres:
+		var expr strings.Builder
+		expr.WriteString(column)
+		for i, key := range path {
+			if i == len(path)-1 {
+				expr.WriteString("->>")
+			} else {
+				expr.WriteString("->")
+			}
+			expr.WriteString("'" + key + "'")
+		}
+		return expr.String()
+	case DialectOracle, DialectSQLServer:
+		return "JSON_VALUE(" + column + ", '$." + strings.Join(path, ".") + "')"
+	default:
+		return "JSON_EXTRACT(" + column + ", '$." + strings.Join(path, ".") + "')"
+	}
+}
+
+// literal renders a bound value as an SQL literal: strings and dates are
+// single-quoted with embedded quotes doubled, numbers and booleans are raw,
+// nil is NULL and arrays are ARRAY[...].
+func (d Dialect) literal(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		if d.backslashEscapes() {
+			v = strings.ReplaceAll(v, `\`, `\\`)
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return d.literal(v.Format(time.RFC3339Nano))
+	case []any:
+		elems := make([]string, len(v))
+		for i, elem := range v {
+			elems[i] = d.literal(elem)
+		}
+		return "ARRAY[" + strings.Join(elems, ", ") + "]"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return d.literal(fmt.Sprint(v))
+	}
+}
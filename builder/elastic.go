@@ -0,0 +1,201 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasvillarinho/restql/parser"
+)
+
+// ToElasticQuery translates a filter into an Elasticsearch query, so the same
+// filter grammar can back a search index. The result is the value of the
+// request's "query" key and encodes to JSON as is.
+//
+// AND maps to a bool "filter" clause, OR to "should" and negations to
+// "must_not". Comparisons map to "term", "terms" and "range"; LIKE (and ~) and ILIKE
+// to "wildcard"; REGEXP to "regexp"; and NULL checks to "exists". Arithmetic
+// and CONTAINS have no Elasticsearch equivalent and are rejected. When opts
+// are given, the filter is validated with them first, as in
+// ValidateFilterString.
+//
+// Example:
+//
+//	filter, _ := parser.ParseFilter("age>18 && status IN ('active','pending')")
+//	query, err := builder.ToElasticQuery(filter)
+//	body, _ := json.Marshal(map[string]any{"query": query})
+//	// {"query":{"bool":{"filter":[{"range":{"age":{"gt":18}}},{"terms":{"status":["active","pending"]}}]}}}
+func ToElasticQuery(filter *parser.Filter, opts ...ValidateOption) (map[string]any, error) {
+	if err := filter.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateFilterAST(filter, opts...); err != nil {
+		return nil, err
+	}
+	if filter == nil || filter.Expression == nil || len(filter.Expression.And) == 0 {
+		return map[string]any{"match_all": map[string]any{}}, nil
+	}
+	return elasticOrExpr(filter.Expression)
+}
+
+// elasticOrExpr translates an OR expression, emitting a bool "should" clause
+// for several branches.
+func elasticOrExpr(expr *parser.OrExpr) (map[string]any, error) {
+	if len(expr.And) == 1 {
+		return elasticAndExpr(expr.And[0])
+	}
+
+	branches := make([]any, 0, len(expr.And))
+	for _, and := range expr.And {
+		query, err := elasticAndExpr(and)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, query)
+	}
+	return map[string]any{"bool": map[string]any{"should": branches, "minimum_should_match": 1}}, nil
+}
+
+// elasticAndExpr translates an AND expression, emitting a bool "filter"
+// clause for several comparisons.
+func elasticAndExpr(expr *parser.AndExpr) (map[string]any, error) {
+	if len(expr.Comparison) == 1 {
+		return elasticComparison(expr.Comparison[0])
+	}
+
+	conditions := make([]any, 0, len(expr.Comparison))
+	for _, comp := range expr.Comparison {
+		query, err := elasticComparison(comp)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, query)
+	}
+	return map[string]any{"bool": map[string]any{"filter": conditions}}, nil
+}
+
+// elasticComparison translates a single comparison into a leaf query.
+func elasticComparison(comp *parser.Comparison) (map[string]any, error) {
+	if comp.Left.SubExpr != nil {
+		query, err := elasticOrExpr(comp.Left.SubExpr)
+		if err != nil || !comp.Not {
+			return query, err
+		}
+		return mustNot(query), nil
+	}
+
+	// Negated comparisons (!age>18) match documents the comparison doesn't
+	if comp.Not && !comp.Bare() {
+		positive := *comp
+		positive.Not = false
+		query, err := elasticComparison(&positive)
+		if err != nil {
+			return nil, err
+		}
+		return mustNot(query), nil
+	}
+
+	field := comp.Left.Field
+	if len(comp.Left.Arith) > 0 {
+		return nil, fmt.Errorf("arithmetic on field '%s' is not supported by Elasticsearch", field)
+	}
+
+	if comp.Null != nil {
+		notNull, err := isNotNull(field, comp.Op, comp.Null)
+		if err != nil {
+			return nil, err
+		}
+		exists := map[string]any{"exists": map[string]any{"field": field}}
+		if notNull {
+			return exists, nil
+		}
+		return mustNot(exists), nil
+	}
+
+	// Bare boolean predicates (active, !active)
+	if comp.Op == nil || comp.Right == nil {
+		return map[string]any{"term": map[string]any{field: !comp.Not}}, nil
+	}
+
+	// Range literals match both bounds inclusively
+	if comp.To != nil {
+		low, _ := comp.Right.Resolve()
+		high, _ := comp.To.Resolve()
+		query := map[string]any{"range": map[string]any{field: map[string]any{"gte": low, "lte": high}}}
+		switch {
+		case comp.Op.Equal:
+			return query, nil
+		case comp.Op.NotEqual:
+			return mustNot(query), nil
+		default:
+			return nil, fmt.Errorf("operator %s on field '%s' cannot compare with a range", comp.Op.String(), field)
+		}
+	}
+
+	value, _ := comp.Right.Resolve()
+	op := comp.Op.ResolveTilde(false)
+
+	switch {
+	case op.Equal, op.Is, op.NullSafeEqual, op.NotDistinctFrom:
+		return map[string]any{"term": map[string]any{field: value}}, nil
+	case op.NotEqual, op.DistinctFrom:
+		return mustNot(map[string]any{"term": map[string]any{field: value}}), nil
+	case op.Greater:
+		return elasticRange(field, "gt", value), nil
+	case op.GreaterOrEqual:
+		return elasticRange(field, "gte", value), nil
+	case op.Less:
+		return elasticRange(field, "lt", value), nil
+	case op.LessOrEqual:
+		return elasticRange(field, "lte", value), nil
+	case op.In:
+		return map[string]any{"terms": map[string]any{field: asList(value)}}, nil
+	case op.NotIn:
+		return mustNot(map[string]any{"terms": map[string]any{field: asList(value)}}), nil
+	case op.Like, op.NotLike, op.ILike, op.NotILike:
+		wildcard := map[string]any{"value": likeToWildcard(fmt.Sprint(value))}
+		if op.ILike || op.NotILike {
+			wildcard["case_insensitive"] = true
+		}
+		query := map[string]any{"wildcard": map[string]any{field: wildcard}}
+		if op.NotLike || op.NotILike {
+			return mustNot(query), nil
+		}
+		return query, nil
+	case op.Regexp:
+		return map[string]any{"regexp": map[string]any{field: map[string]any{"value": fmt.Sprint(value)}}}, nil
+	case op.NotRegexp:
+		return mustNot(map[string]any{"regexp": map[string]any{field: map[string]any{"value": fmt.Sprint(value)}}}), nil
+	default:
+		return nil, fmt.Errorf("operator %s on field '%s' is not supported by Elasticsearch", op.String(), field)
+	}
+}
+
+// elasticRange builds a range query with a single bound.
+func elasticRange(field, bound string, value any) map[string]any {
+	return map[string]any{"range": map[string]any{field: map[string]any{bound: value}}}
+}
+
+// mustNot negates a query with a bool "must_not" clause.
+func mustNot(query map[string]any) map[string]any {
+	return map[string]any{"bool": map[string]any{"must_not": []any{query}}}
+}
+
+// likeToWildcard converts a LIKE pattern into a wildcard pattern: % becomes *
+// and _ becomes ?, and literal wildcard characters are escaped.
+func likeToWildcard(pattern string) string {
+	var sb strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteRune('*')
+		case '_':
+			sb.WriteRune('?')
+		case '*', '?', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
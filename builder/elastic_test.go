@@ -0,0 +1,113 @@
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lucasvillarinho/restql/parser"
+)
+
+func TestToElasticQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected string
+	}{
+		{
+			name:     "equality",
+			filter:   "status='active'",
+			expected: `{"term":{"status":"active"}}`,
+		},
+		{
+			name:     "range",
+			filter:   "age>=18 && age<65",
+			expected: `{"bool":{"filter":[{"range":{"age":{"gte":18}}},{"range":{"age":{"lt":65}}}]}}`,
+		},
+		{
+			name:     "range literal",
+			filter:   "age=18..65",
+			expected: `{"range":{"age":{"gte":18,"lte":65}}}`,
+		},
+		{
+			name:     "terms",
+			filter:   "status IN ('active', 'pending')",
+			expected: `{"terms":{"status":["active","pending"]}}`,
+		},
+		{
+			name:     "NOT IN",
+			filter:   "id NOT IN (1, 2)",
+			expected: `{"bool":{"must_not":[{"terms":{"id":[1,2]}}]}}`,
+		},
+		{
+			name:     "wildcard",
+			filter:   "name ILIKE 'j_hn%'",
+			expected: `{"wildcard":{"name":{"value":"j?hn*","case_insensitive":true}}}`,
+		},
+		{
+			name:     "IS NULL",
+			filter:   "deleted_at IS NULL",
+			expected: `{"bool":{"must_not":[{"exists":{"field":"deleted_at"}}]}}`,
+		},
+		{
+			name:     "IS NOT NULL",
+			filter:   "email IS NOT NULL",
+			expected: `{"exists":{"field":"email"}}`,
+		},
+		{
+			name:     "negated comparison",
+			filter:   "!age>18",
+			expected: `{"bool":{"must_not":[{"range":{"age":{"gt":18}}}]}}`,
+		},
+		{
+			name:   "nested AND/OR",
+			filter: "(status='active' || status='trial') && !(age<18 || banned=true)",
+			expected: `{"bool":{"filter":[
+				{"bool":{"should":[{"term":{"status":"active"}},{"term":{"status":"trial"}}],"minimum_should_match":1}},
+				{"bool":{"must_not":[
+					{"bool":{"should":[{"range":{"age":{"lt":18}}},{"term":{"banned":true}}],"minimum_should_match":1}}
+				]}}
+			]}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			query, err := ToElasticQuery(filter)
+			require.NoError(t, err)
+
+			body, err := json.Marshal(query)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(body))
+		})
+	}
+
+	t.Run("empty filter matches everything", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := ToElasticQuery(nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"match_all": map[string]any{}}, query)
+	})
+
+	t.Run("validates fields", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && password='x'")
+		require.NoError(t, err)
+
+		_, err = ToElasticQuery(filter, WithAllowedFields([]string{"age"}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+}
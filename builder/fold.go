@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/lucasvillarinho/restql/parser"
+)
+
+// foldOrEquals folds the OR branches that compare the same field with = or
+// IN into a single IN comparison placed at the first branch, dropping
+// repeated values (e.g. "a=1 || b=2 || a=3" becomes "a IN (1,3) || b=2").
+// Other branches are kept in place and the AST is not modified. Case-folded
+// fields are not folded, since IN would compare them case-sensitively.
+func foldOrEquals(and []*parser.AndExpr, caseFold map[string]bool) []*parser.AndExpr {
+	counts := make(map[string]int)
+	for _, andExpr := range and {
+		if comp := foldable(andExpr); comp != nil && !caseFold[comp.Left.Field] {
+			counts[comp.Left.Field]++
+		}
+	}
+
+	folded := make([]*parser.AndExpr, 0, len(and))
+	groups := make(map[string]*parser.Array)
+	seen := make(map[string]bool)
+	for _, andExpr := range and {
+		comp := foldable(andExpr)
+		if comp == nil || counts[comp.Left.Field] < 2 {
+			folded = append(folded, andExpr)
+			continue
+		}
+
+		field := comp.Left.Field
+		array, ok := groups[field]
+		if !ok {
+			array = &parser.Array{}
+			groups[field] = array
+			folded = append(folded, &parser.AndExpr{Comparison: []*parser.Comparison{{
+				Left:  comp.Left,
+				Op:    &parser.Operator{In: true},
+				Right: &parser.Value{Array: array},
+			}}})
+		}
+
+		values := []*parser.Value{comp.Right}
+		if comp.Right.Array != nil {
+			values = comp.Right.Array.Values
+		}
+		for _, val := range values {
+			resolved, _ := val.Resolve()
+			key := fmt.Sprintf("%s\x00%T\x00%v", field, resolved, resolved)
+			if !seen[key] {
+				seen[key] = true
+				array.Values = append(array.Values, val)
+			}
+		}
+	}
+	return folded
+}
+
+// foldable returns the comparison of an OR branch that is a single
+// "field = value" or "field IN (...)", or nil.
+func foldable(andExpr *parser.AndExpr) *parser.Comparison {
+	if andExpr == nil || len(andExpr.Comparison) != 1 {
+		return nil
+	}
+	comp := andExpr.Comparison[0]
+	if comp == nil || comp.Not || comp.Left == nil || comp.Left.Field == "" || len(comp.Left.Arith) > 0 {
+		return nil
+	}
+	if comp.Op == nil || comp.Right == nil || comp.Null != nil || comp.To != nil {
+		return nil
+	}
+	if comp.Op.Equal && comp.Right.Array == nil || comp.Op.In && comp.Right.Array != nil {
+		return comp
+	}
+	return nil
+}
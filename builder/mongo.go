@@ -0,0 +1,208 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lucasvillarinho/restql/parser"
+)
+
+// ToMongoFilter translates a filter into a MongoDB query document, so the
+// same filter grammar can back a MongoDB collection. The document converts
+// to bson.M (bson.M(doc)) and can be passed to the driver as is.
+//
+// Comparisons map to $eq, $ne, $gt, $gte, $lt, $lte, $in and $nin; LIKE (and ~),
+// ILIKE and REGEXP to $regex; CONTAINS to $all; IS NOT NULL to $exists (IS
+// NULL matches missing fields too); and AND/OR to $and/$or. Arithmetic has no MongoDB equivalent and is rejected.
+// When opts are given, the filter is validated with them first, as in
+// ValidateFilterString.
+//
+// Example:
+//
+//	filter, _ := parser.ParseFilter("age>18 && status IN ('active','pending')")
+//	doc, err := builder.ToMongoFilter(filter, builder.WithAllowedFields([]string{"age", "status"}))
+//	// {"$and": [{"age": {"$gt": 18}}, {"status": {"$in": ["active", "pending"]}}]}
+func ToMongoFilter(filter *parser.Filter, opts ...ValidateOption) (map[string]any, error) {
+	if err := filter.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateFilterAST(filter, opts...); err != nil {
+		return nil, err
+	}
+	if filter == nil || filter.Expression == nil || len(filter.Expression.And) == 0 {
+		return map[string]any{}, nil
+	}
+	return mongoOrExpr(filter.Expression)
+}
+
+// validateFilterAST validates a parsed filter against opts. Without options
+// every filter is valid.
+func validateFilterAST(filter *parser.Filter, opts ...ValidateOption) error {
+	if len(opts) == 0 {
+		return nil
+	}
+	return NewQueryBuilder("").SetFilter(filter).Validate(opts...).validate()
+}
+
+// mongoOrExpr translates an OR expression, emitting $or for several branches.
+func mongoOrExpr(expr *parser.OrExpr) (map[string]any, error) {
+	if len(expr.And) == 1 {
+		return mongoAndExpr(expr.And[0])
+	}
+
+	branches := make([]any, 0, len(expr.And))
+	for _, and := range expr.And {
+		doc, err := mongoAndExpr(and)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, doc)
+	}
+	return map[string]any{"$or": branches}, nil
+}
+
+// mongoAndExpr translates an AND expression, emitting $and for several
+// comparisons.
+func mongoAndExpr(expr *parser.AndExpr) (map[string]any, error) {
+	if len(expr.Comparison) == 1 {
+		return mongoComparison(expr.Comparison[0])
+	}
+
+	conditions := make([]any, 0, len(expr.Comparison))
+	for _, comp := range expr.Comparison {
+		doc, err := mongoComparison(comp)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, doc)
+	}
+	return map[string]any{"$and": conditions}, nil
+}
+
+// mongoComparison translates a single comparison into {field: {op: value}}.
+func mongoComparison(comp *parser.Comparison) (map[string]any, error) {
+	// Negated groups match documents that match none of their branches
+	if comp.Left.SubExpr != nil {
+		doc, err := mongoOrExpr(comp.Left.SubExpr)
+		if err != nil || !comp.Not {
+			return doc, err
+		}
+		return map[string]any{"$nor": []any{doc}}, nil
+	}
+
+	// Negated comparisons (!age>18) match documents the comparison doesn't
+	if comp.Not && !comp.Bare() {
+		positive := *comp
+		positive.Not = false
+		doc, err := mongoComparison(&positive)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$nor": []any{doc}}, nil
+	}
+
+	field := comp.Left.Field
+	if len(comp.Left.Arith) > 0 {
+		return nil, fmt.Errorf("arithmetic on field '%s' is not supported by MongoDB", field)
+	}
+
+	// IS NULL matches missing fields as well as explicit nulls
+	if comp.Null != nil {
+		notNull, err := isNotNull(field, comp.Op, comp.Null)
+		if err != nil {
+			return nil, err
+		}
+		if notNull {
+			return map[string]any{field: map[string]any{"$exists": true, "$ne": nil}}, nil
+		}
+		return map[string]any{field: map[string]any{"$eq": nil}}, nil
+	}
+
+	// Bare boolean predicates (active, !active)
+	if comp.Op == nil || comp.Right == nil {
+		return map[string]any{field: map[string]any{"$eq": !comp.Not}}, nil
+	}
+
+	// Range literals match both bounds inclusively
+	if comp.To != nil {
+		low, _ := comp.Right.Resolve()
+		high, _ := comp.To.Resolve()
+		cond := map[string]any{"$gte": low, "$lte": high}
+		switch {
+		case comp.Op.Equal:
+			return map[string]any{field: cond}, nil
+		case comp.Op.NotEqual:
+			return map[string]any{field: map[string]any{"$not": cond}}, nil
+		default:
+			return nil, fmt.Errorf("operator %s on field '%s' cannot compare with a range", comp.Op.String(), field)
+		}
+	}
+
+	value, _ := comp.Right.Resolve()
+	op := comp.Op.ResolveTilde(false)
+
+	var cond map[string]any
+	switch {
+	case op.Equal, op.Is, op.NullSafeEqual, op.NotDistinctFrom:
+		cond = map[string]any{"$eq": value}
+	case op.NotEqual, op.DistinctFrom:
+		cond = map[string]any{"$ne": value}
+	case op.Greater:
+		cond = map[string]any{"$gt": value}
+	case op.GreaterOrEqual:
+		cond = map[string]any{"$gte": value}
+	case op.Less:
+		cond = map[string]any{"$lt": value}
+	case op.LessOrEqual:
+		cond = map[string]any{"$lte": value}
+	case op.In:
+		cond = map[string]any{"$in": asList(value)}
+	case op.NotIn:
+		cond = map[string]any{"$nin": asList(value)}
+	case op.Contains:
+		cond = map[string]any{"$all": asList(value)}
+	case op.Like, op.NotLike, op.ILike, op.NotILike:
+		cond = map[string]any{"$regex": likeToRegexp(fmt.Sprint(value))}
+		if op.ILike || op.NotILike {
+			cond["$options"] = "i"
+		}
+		if op.NotLike || op.NotILike {
+			cond = map[string]any{"$not": cond}
+		}
+	case op.Regexp:
+		cond = map[string]any{"$regex": fmt.Sprint(value)}
+	case op.NotRegexp:
+		cond = map[string]any{"$not": map[string]any{"$regex": fmt.Sprint(value)}}
+	default:
+		return nil, fmt.Errorf("operator %s on field '%s' is not supported by MongoDB", op.String(), field)
+	}
+	return map[string]any{field: cond}, nil
+}
+
+// asList returns a resolved array value as is and wraps a scalar in a list.
+func asList(value any) []any {
+	if values, ok := value.([]any); ok {
+		return values
+	}
+	return []any{value}
+}
+
+// likeToRegexp converts a LIKE pattern into an anchored regular expression:
+// % matches any run of characters and _ a single character.
+func likeToRegexp(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
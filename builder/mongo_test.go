@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lucasvillarinho/restql/parser"
+)
+
+func TestToMongoFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   string
+		expected map[string]any
+	}{
+		{
+			name:     "equality",
+			filter:   "status='active'",
+			expected: map[string]any{"status": map[string]any{"$eq": "active"}},
+		},
+		{
+			name:   "range",
+			filter: "age>=18 && age<65",
+			expected: map[string]any{"$and": []any{
+				map[string]any{"age": map[string]any{"$gte": 18}},
+				map[string]any{"age": map[string]any{"$lt": 65}},
+			}},
+		},
+		{
+			name:     "range literal",
+			filter:   "age=18..65",
+			expected: map[string]any{"age": map[string]any{"$gte": 18, "$lte": 65}},
+		},
+		{
+			name:     "IN",
+			filter:   "status IN ('active', 'pending')",
+			expected: map[string]any{"status": map[string]any{"$in": []any{"active", "pending"}}},
+		},
+		{
+			name:     "NOT IN",
+			filter:   "id NOT IN (1, 2)",
+			expected: map[string]any{"id": map[string]any{"$nin": []any{1, 2}}},
+		},
+		{
+			name:     "IS NULL",
+			filter:   "deleted_at IS NULL",
+			expected: map[string]any{"deleted_at": map[string]any{"$eq": nil}},
+		},
+		{
+			name:     "IS NOT NULL",
+			filter:   "email != null",
+			expected: map[string]any{"email": map[string]any{"$exists": true, "$ne": nil}},
+		},
+		{
+			name:     "LIKE",
+			filter:   "name LIKE 'J_hn%'",
+			expected: map[string]any{"name": map[string]any{"$regex": "^J.hn.*$"}},
+		},
+		{
+			name:     "~ as LIKE",
+			filter:   "name~'John%'",
+			expected: map[string]any{"name": map[string]any{"$regex": "^John.*$"}},
+		},
+		{
+			name:     "NOT ILIKE",
+			filter:   "email NOT ILIKE '%.test'",
+			expected: map[string]any{"email": map[string]any{"$not": map[string]any{"$regex": `^.*\.test$`, "$options": "i"}}},
+		},
+		{
+			name:     "negated comparison",
+			filter:   "!age>18",
+			expected: map[string]any{"$nor": []any{map[string]any{"age": map[string]any{"$gt": 18}}}},
+		},
+		{
+			name:   "nested AND/OR",
+			filter: "(status='active' || status='trial') && !(age<18 || banned=true)",
+			expected: map[string]any{"$and": []any{
+				map[string]any{"$or": []any{
+					map[string]any{"status": map[string]any{"$eq": "active"}},
+					map[string]any{"status": map[string]any{"$eq": "trial"}},
+				}},
+				map[string]any{"$nor": []any{
+					map[string]any{"$or": []any{
+						map[string]any{"age": map[string]any{"$lt": 18}},
+						map[string]any{"banned": map[string]any{"$eq": true}},
+					}},
+				}},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			doc, err := ToMongoFilter(filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, doc)
+		})
+	}
+
+	t.Run("empty filter matches everything", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := ToMongoFilter(nil)
+		require.NoError(t, err)
+		assert.Empty(t, doc)
+	})
+
+	t.Run("validates fields", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && password='x'")
+		require.NoError(t, err)
+
+		_, err = ToMongoFilter(filter, WithAllowedFields([]string{"age"}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+
+	t.Run("rejects arithmetic", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("price * quantity > 100")
+		require.NoError(t, err)
+
+		_, err = ToMongoFilter(filter)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not supported by MongoDB")
+	})
+}
@@ -1,11 +1,21 @@
 package builder
 
+import (
+	"slices"
+	"strings"
+
+	"github.com/lucasvillarinho/restql/parser"
+)
+
 // ValidateOption is a function that configures a Validator.
 type ValidateOption func(*Validator)
 
 // WithAllowedFields sets the allowed fields whitelist for validation.
 // Only fields in this list will be permitted in filters, selects, and sorts.
+// The list is copied, so a base set of options can be shared across endpoints
+// and extended per endpoint by adding another WithAllowedFields.
 func WithAllowedFields(fields []string) ValidateOption {
+	fields = slices.Clone(fields)
 	return func(v *Validator) {
 		if v.allowedFields == nil {
 			v.allowedFields = make(map[string]bool)
@@ -16,6 +26,205 @@ func WithAllowedFields(fields []string) ValidateOption {
 	}
 }
 
+// WithForbiddenFields sets the forbidden fields blacklist for validation.
+// Any filter, select, or sort referencing one of these fields is rejected,
+// while all other fields are permitted. When combined with WithAllowedFields,
+// forbidden fields are rejected even if they are also allowed. Fields match
+// in any case and with a base-table qualifier, e.g. "SSN" or "users.ssn".
+// The list is copied like WithAllowedFields'.
+func WithForbiddenFields(fields []string) ValidateOption {
+	fields = slices.Clone(fields)
+	return func(v *Validator) {
+		if v.forbiddenFields == nil {
+			v.forbiddenFields = make(map[string]bool)
+		}
+		for _, field := range fields {
+			v.forbiddenFields[strings.ToLower(field)] = true
+		}
+	}
+}
+
+// WithFieldOperators restricts the filter operators allowed per field, e.g.
+// {"name": {"=", "LIKE"}, "age": {"=", ">", "<"}}, so expensive or meaningless
+// comparisons can be rejected. Operators are named as emitted ("=", "!=",
+// ">=", "LIKE", "NOT IN", "IS", "REGEXP", "CONTAINS", ...), in any case;
+// "<>" is "!=". Fields without an entry allow every operator.
+func WithFieldOperators(operators map[string][]string) ValidateOption {
+	fieldOperators := make(map[string]map[string]bool, len(operators))
+	for field, ops := range operators {
+		fieldOperators[field] = operatorSet(ops)
+	}
+	return func(v *Validator) {
+		if v.fieldOperators == nil {
+			v.fieldOperators = make(map[string]map[string]bool)
+		}
+		for field, allowed := range fieldOperators {
+			v.fieldOperators[field] = allowed
+		}
+	}
+}
+
+// WithAllowedValues restricts the values a field may be compared with, e.g.
+// WithAllowedValues("status", "active", "pending", "archived") for an
+// enum-like column, so typos and probing are rejected. Every compared value,
+// including each IN list element, must be listed; pattern operators (LIKE,
+// ILIKE, REGEXP) are not checked. Values match on their text, so "1" also
+// allows the number 1.
+func WithAllowedValues(field string, values ...string) ValidateOption {
+	allowed := make(map[string]bool, len(values))
+	for _, value := range values {
+		allowed[value] = true
+	}
+	return func(v *Validator) {
+		if v.allowedValues == nil {
+			v.allowedValues = make(map[string]map[string]bool)
+		}
+		v.allowedValues[field] = allowed
+	}
+}
+
+// WithAllowedOperators restricts the filter operators allowed on any field,
+// e.g. WithAllowedOperators("=", "IN") on an endpoint that only serves
+// indexable lookups. Operators are named as in WithFieldOperators.
+func WithAllowedOperators(ops ...string) ValidateOption {
+	allowed := operatorSet(ops)
+	return func(v *Validator) {
+		if v.allowedOperators == nil {
+			v.allowedOperators = make(map[string]bool)
+		}
+		for op := range allowed {
+			v.allowedOperators[op] = true
+		}
+	}
+}
+
+// WithForbiddenOperators rejects filters using any of ops, e.g.
+// WithForbiddenOperators("LIKE", "ILIKE") to keep unindexed pattern scans off
+// a reporting API. Operators are named as in WithFieldOperators; forbidden
+// operators are rejected even if they are also allowed.
+func WithForbiddenOperators(ops ...string) ValidateOption {
+	forbidden := operatorSet(ops)
+	return func(v *Validator) {
+		if v.forbiddenOperators == nil {
+			v.forbiddenOperators = make(map[string]bool)
+		}
+		for op := range forbidden {
+			v.forbiddenOperators[op] = true
+		}
+	}
+}
+
+// operatorSet normalizes operator names to upper case, with "<>" as "!=".
+func operatorSet(ops []string) map[string]bool {
+	set := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		op = strings.ToUpper(strings.TrimSpace(op))
+		if op == "<>" {
+			op = "!="
+		}
+		set[op] = true
+	}
+	return set
+}
+
+// WithColumnMapper translates API field names to columns programmatically
+// (e.g. camelCase to snake_case). The mapper runs after the forbidden and
+// allowed field checks; returning ok=false rejects the field. Mapped columns
+// are used in the emitted SQL.
+func WithColumnMapper(mapper func(apiField string) (column string, ok bool)) ValidateOption {
+	return func(v *Validator) {
+		v.columnMapper = mapper
+	}
+}
+
+// WithFieldTypes declares field types. Quoted numbers compared with a field
+// declared as parser.KindInt or parser.KindFloat are bound as numbers, so
+// age='18' binds int 18 instead of the string "18".
+func WithFieldTypes(types map[string]parser.ValueKind) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetFieldTypes(types)
+	}
+}
+
+// WithCaseFold marks fields compared case-insensitively with = and !=;
+// see QueryBuilder.SetCaseFold.
+func WithCaseFold(fields ...string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetCaseFold(fields...)
+	}
+}
+
+// WithEnsureFields always selects fields when the client lists fields, e.g.
+// the primary key; see QueryBuilder.EnsureFields.
+func WithEnsureFields(fields ...string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.EnsureFields(fields...)
+	}
+}
+
+// WithTableAlias sets an alias for the table, emitted as "FROM users AS u".
+// Unqualified fields are prefixed with the alias; see QueryBuilder.SetTableAlias.
+func WithTableAlias(alias string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetTableAlias(alias)
+	}
+}
+
+// WithHasMany declares a one-to-many relationship filtered with EXISTS;
+// see QueryBuilder.HasMany.
+func WithHasMany(table, on string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.HasMany(table, on)
+	}
+}
+
+// WithJSONColumns declares JSON columns whose dotted fields are emitted as
+// path extractions; see QueryBuilder.SetJSONColumns. Allowing or forbidding
+// a JSON column applies to all of its paths.
+func WithJSONColumns(columns ...string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetJSONColumns(columns...)
+	}
+}
+
+// WithCaseInsensitiveFields enables case-insensitive field matching.
+// Fields are matched against the allowed fields ignoring case, and the emitted
+// SQL uses the registered casing (e.g. "Status" becomes "status").
+func WithCaseInsensitiveFields() ValidateOption {
+	return func(v *Validator) {
+		v.caseInsensitive = true
+	}
+}
+
+// WithStripDisallowedFields removes disallowed fields from the query instead
+// of rejecting it: filter comparisons, selected fields and sorts that
+// reference them are dropped, and AND/OR groups left empty are pruned. A
+// filter that is entirely disallowed yields no WHERE clause. Use it for public
+// search APIs where a partial result is better than an error.
+func WithStripDisallowedFields() ValidateOption {
+	return func(v *Validator) {
+		v.stripDisallowed = true
+	}
+}
+
+// WithSnakeCaseFields converts camelCase fields to snake_case before they are
+// matched against the allowed and forbidden fields and emitted, so a camelCase
+// API can filter a snake_case schema without an alias map ("createdAt" becomes
+// "created_at", "userID" becomes "user_id"). List allowed fields in snake_case.
+func WithSnakeCaseFields() ValidateOption {
+	return func(v *Validator) {
+		v.snakeCase = true
+	}
+}
+
+// WithRequireFilter requires at least one filter condition.
+// Use it on heavy endpoints that must never be queried without a filter.
+func WithRequireFilter() ValidateOption {
+	return func(v *Validator) {
+		v.requireFilter = true
+	}
+}
+
 // WithMaxLimit sets the maximum allowed limit value.
 // If the query requests a limit greater than this, validation will fail.
 func WithMaxLimit(max int) ValidateOption {
@@ -24,6 +233,15 @@ func WithMaxLimit(max int) ValidateOption {
 	}
 }
 
+// WithUnboundedLimit accepts "limit=all" (or "limit=-1") and emits no LIMIT
+// clause. Without it such requests are rejected; only enable it on endpoints
+// that legitimately return every row, such as admin exports.
+func WithUnboundedLimit() ValidateOption {
+	return func(v *Validator) {
+		v.unboundedLimit = true
+	}
+}
+
 // WithMaxOffset sets the maximum allowed offset value.
 // If the query requests an offset greater than this, validation will fail.
 func WithMaxOffset(max int) ValidateOption {
@@ -31,3 +249,75 @@ func WithMaxOffset(max int) ValidateOption {
 		v.maxOffset = &max
 	}
 }
+
+// WithMaxFields sets the maximum number of fields a query may select.
+// If the query requests more fields than this, validation will fail.
+func WithMaxFields(max int) ValidateOption {
+	return func(v *Validator) {
+		v.maxFields = &max
+	}
+}
+
+// WithMaxSortFields sets the maximum number of fields a client may sort by,
+// bounding the cost of multi-column sorts. Default sort fields are not counted.
+// If the query requests more sort fields than this, validation will fail.
+func WithMaxSortFields(max int) ValidateOption {
+	return func(v *Validator) {
+		v.maxSortFields = &max
+	}
+}
+
+// WithMaxArgs sets the maximum number of arguments a query may bind, e.g. to
+// stay under a driver's placeholder limit. Large IN lists count one argument
+// per value. If the query binds more, validation will fail.
+func WithMaxArgs(max int) ValidateOption {
+	return func(v *Validator) {
+		v.maxArgs = &max
+	}
+}
+
+// SortDuplicates controls how a sort that repeats a field is handled.
+type SortDuplicates int
+
+const (
+	// SortDuplicatesAllow keeps repeated sort fields as given. This is the default.
+	SortDuplicatesAllow SortDuplicates = iota
+	// SortDuplicatesError rejects a sort that repeats a field.
+	SortDuplicatesError
+	// SortDuplicatesKeepFirst keeps only the first occurrence of each field.
+	SortDuplicatesKeepFirst
+)
+
+// WithSortDuplicates sets how a client sort that repeats a field
+// (e.g. "name,-name") is handled.
+func WithSortDuplicates(mode SortDuplicates) ValidateOption {
+	return func(v *Validator) {
+		v.sortDuplicates = mode
+	}
+}
+
+// WithSortCollations sets the collation emitted for sort fields;
+// see QueryBuilder.SetSortCollations.
+func WithSortCollations(collations map[string]string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetSortCollations(collations)
+	}
+}
+
+// WithSortOrders declares custom sort orders for "field:custom" sorts;
+// see QueryBuilder.SetSortOrders.
+func WithSortOrders(orders map[string][]string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetSortOrders(orders)
+	}
+}
+
+// WithDefaultSort sets a stable default sort used as a tiebreaker.
+// The fields are appended to the client sort when not already present, and
+// supply the whole ORDER BY when the client omits sort. Default sort fields
+// are validated against the allowed fields like any client sort field.
+func WithDefaultSort(fields ...string) ValidateOption {
+	return func(v *Validator) {
+		v.qb.SetDefaultSort(fields...)
+	}
+}
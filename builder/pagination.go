@@ -0,0 +1,51 @@
+package builder
+
+// Pager is implemented by QueryBuilder and Validator and exposes the
+// requested page window.
+type Pager interface {
+	Limit() int
+	Offset() int
+}
+
+// PaginationMeta describes the page of results a query returns, ready to be
+// encoded alongside the data in an API response.
+type PaginationMeta struct {
+	Page       int  `json:"page"`
+	PerPage    int  `json:"per_page"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+// Paginate computes pagination metadata from the query's limit and offset and
+// the total row count, typically obtained with ToCountSQL. A query without a
+// limit returns every row from the offset as a single page.
+//
+// Example:
+//
+//	countSQL, countArgs, _ := qb.ToCountSQL()
+//	db.QueryRow(countSQL, countArgs...).Scan(&total)
+//	meta := builder.Paginate(qb, total)
+func Paginate(p Pager, total int) PaginationMeta {
+	limit, offset := p.Limit(), p.Offset()
+	meta := PaginationMeta{
+		Page:    1,
+		PerPage: limit,
+		Total:   total,
+		HasPrev: offset > 0,
+	}
+
+	if limit == 0 {
+		meta.PerPage = max(total-offset, 0)
+		if total > 0 {
+			meta.TotalPages = 1
+		}
+		return meta
+	}
+
+	meta.Page = offset/limit + 1
+	meta.TotalPages = (total + limit - 1) / limit
+	meta.HasNext = offset+limit < total
+	return meta
+}
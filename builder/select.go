@@ -0,0 +1,92 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// aggregateFunctions are the aggregate functions allowed in selected fields.
+var aggregateFunctions = map[string]bool{
+	"AVG":   true,
+	"COUNT": true,
+	"MAX":   true,
+	"MIN":   true,
+	"SUM":   true,
+}
+
+// selectExpr represents a parsed selected field: a column, or a whitelisted
+// aggregate over a column with an optional alias (e.g. "COUNT(id) AS total").
+type selectExpr struct {
+	function string
+	field    string
+	alias    string
+}
+
+// parseSelect parses a selected field. Plain fields are returned as is;
+// aggregates are upper-cased and checked, and only COUNT accepts "*".
+func parseSelect(s string) (selectExpr, error) {
+	expr := strings.TrimSpace(s)
+	open := strings.IndexByte(expr, '(')
+	if open < 0 {
+		return selectExpr{field: expr}, nil
+	}
+
+	function := strings.ToUpper(strings.TrimSpace(expr[:open]))
+	if !aggregateFunctions[function] {
+		return selectExpr{}, fmt.Errorf("function '%s' is not allowed in fields", strings.TrimSpace(expr[:open]))
+	}
+	closing := strings.IndexByte(expr, ')')
+	if closing < open {
+		return selectExpr{}, fmt.Errorf("invalid field expression '%s'", s)
+	}
+
+	e := selectExpr{function: function, field: strings.TrimSpace(expr[open+1 : closing])}
+	if !identPattern.MatchString(e.field) && (e.field != "*" || function != "COUNT") {
+		return selectExpr{}, fmt.Errorf("invalid field expression '%s'", s)
+	}
+
+	if rest := strings.TrimSpace(expr[closing+1:]); rest != "" {
+		keyword, alias, ok := strings.Cut(rest, " ")
+		alias = strings.TrimSpace(alias)
+		if !ok || !strings.EqualFold(keyword, "AS") || !identPattern.MatchString(alias) || strings.Contains(alias, ".") {
+			return selectExpr{}, fmt.Errorf("invalid field expression '%s'", s)
+		}
+		e.alias = alias
+	}
+	return e, nil
+}
+
+// String returns the selected field in client format.
+func (e selectExpr) String() string {
+	if e.function == "" {
+		return e.field
+	}
+	s := e.function + "(" + e.field + ")"
+	if e.alias != "" {
+		s += " AS " + e.alias
+	}
+	return s
+}
+
+// selectSQL returns the SQL for a selected field, qualifying and quoting its
+// column and alias.
+func (qb *QueryBuilder) selectSQL(field string) string {
+	e, err := parseSelect(field)
+	if err != nil {
+		qb.fail(err)
+		return field
+	}
+	if e.function == "" {
+		return qb.qualify(field)
+	}
+
+	column := e.field
+	if column != "*" {
+		column = qb.qualify(column)
+	}
+	sql := e.function + "(" + column + ")"
+	if e.alias != "" {
+		sql += " AS " + qb.ident(e.alias)
+	}
+	return sql
+}
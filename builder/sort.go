@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sortFunctions are the functions allowed in sort expressions.
+var sortFunctions = map[string]bool{
+	"COALESCE": true,
+	"LOWER":    true,
+	"UPPER":    true,
+}
+
+// identPattern matches a (possibly qualified) identifier: a column name, e.g.
+// inside a sort function, or a table name such as "public.users".
+var identPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+// sortExpr represents a parsed sort entry: a field, a whitelisted function
+// call over fields (e.g. "-COALESCE(updated_at,created_at)") or a 1-based
+// position in the selected fields (e.g. "-1"). A field with a ":custom"
+// suffix sorts by its declared custom order (e.g. "-status:custom").
+type sortExpr struct {
+	desc     bool
+	custom   bool
+	function string
+	fields   []string
+	position int
+}
+
+// parseSort parses a sort entry. A leading "-" or a ":desc" suffix means
+// descending; ":asc" is accepted too. A ":custom" suffix, which may follow a
+// leading "-", sorts a field by its custom order. Suffixes are case-insensitive.
+func parseSort(s string) (sortExpr, error) {
+	expr, desc := strings.CutPrefix(s, "-")
+	custom := false
+	if i := strings.LastIndexByte(expr, ':'); i >= 0 {
+		direction := expr[i+1:]
+		switch {
+		case strings.EqualFold(direction, "custom"):
+			custom = true
+		case desc:
+			return sortExpr{}, fmt.Errorf("invalid sort expression '%s': use either '-' or a direction suffix", s)
+		case strings.EqualFold(direction, "asc"):
+		case strings.EqualFold(direction, "desc"):
+			desc = true
+		default:
+			return sortExpr{}, fmt.Errorf("invalid sort direction '%s' in '%s': use asc or desc", direction, s)
+		}
+		expr = expr[:i]
+	}
+
+	if custom {
+		if !identPattern.MatchString(expr) {
+			return sortExpr{}, fmt.Errorf("invalid sort expression '%s': custom order applies to a field", s)
+		}
+		return sortExpr{desc: desc, custom: true, fields: []string{expr}}, nil
+	}
+
+	if expr != "" && strings.Trim(expr, "0123456789") == "" {
+		position, err := strconv.Atoi(expr)
+		if err != nil || position == 0 {
+			return sortExpr{}, fmt.Errorf("invalid sort position '%s'", s)
+		}
+		return sortExpr{desc: desc, position: position}, nil
+	}
+
+	open := strings.IndexByte(expr, '(')
+	if open < 0 {
+		if !identPattern.MatchString(expr) {
+			return sortExpr{}, fmt.Errorf("invalid sort field '%s'", s)
+		}
+		return sortExpr{desc: desc, fields: []string{expr}}, nil
+	}
+
+	function := strings.ToUpper(strings.TrimSpace(expr[:open]))
+	if !sortFunctions[function] {
+		return sortExpr{}, fmt.Errorf("function '%s' is not allowed in sort", expr[:open])
+	}
+	if !strings.HasSuffix(expr, ")") {
+		return sortExpr{}, fmt.Errorf("invalid sort expression '%s'", s)
+	}
+
+	args := strings.Split(expr[open+1:len(expr)-1], ",")
+	fields := make([]string, 0, len(args))
+	for _, arg := range args {
+		field := strings.TrimSpace(arg)
+		if !identPattern.MatchString(field) {
+			return sortExpr{}, fmt.Errorf("invalid sort expression '%s'", s)
+		}
+		fields = append(fields, field)
+	}
+
+	return sortExpr{desc: desc, function: function, fields: fields}, nil
+}
+
+// expr returns the SQL expression without direction.
+func (e sortExpr) expr() string {
+	if e.position > 0 {
+		return strconv.Itoa(e.position)
+	}
+	if e.function == "" {
+		return e.fields[0]
+	}
+	return e.function + "(" + strings.Join(e.fields, ", ") + ")"
+}
+
+// String returns the sort entry in client format, with "-" for descending.
+func (e sortExpr) String() string {
+	s := e.expr()
+	if e.custom {
+		s += ":custom"
+	}
+	if e.desc {
+		return "-" + s
+	}
+	return s
+}
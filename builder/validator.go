@@ -1,160 +1,864 @@
 package builder
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/lucasvillarinho/restql/parser"
 )
 
 // Validator validates query parameters against configured rules.
 type Validator struct {
-	qb            *QueryBuilder
-	allowedFields map[string]bool
-	maxLimit      *int
-	maxOffset     *int
+	qb                 *QueryBuilder
+	allowedFields      map[string]bool
+	forbiddenFields    map[string]bool
+	maxLimit           *int
+	maxOffset          *int
+	maxFields          *int
+	maxSortFields      *int
+	maxArgs            *int
+	sortDuplicates     SortDuplicates
+	caseInsensitive    bool
+	snakeCase          bool
+	requireFilter      bool
+	stripDisallowed    bool
+	unboundedLimit     bool
+	fieldOperators     map[string]map[string]bool // Operators allowed per field; fields without an entry allow all
+	allowedOperators   map[string]bool            // Operators allowed on any field; empty allows all
+	forbiddenOperators map[string]bool            // Operators rejected on any field
+	allowedValues      map[string]map[string]bool // Values allowed per field, by text
+	columnMapper       func(string) (string, bool)
+}
+
+// ValidateFilterString parses filter and validates it against opts without
+// building a query, e.g. to pre-validate a request in middleware. It returns
+// the parse error or the first validation error, or nil if the filter is valid.
+func ValidateFilterString(filter string, opts ...ValidateOption) error {
+	parsed, err := parser.ParseFilter(filter)
+	if err != nil {
+		return err
+	}
+	return validateFilterAST(parsed, opts...)
 }
 
 // ToSQL builds the SQL query after validating all parameters.
 // Returns an error if any validation fails.
 func (v *Validator) ToSQL() (string, []any, error) {
-	// Validate fields (SELECT clause)
-	if len(v.qb.fields) > 0 && len(v.allowedFields) > 0 {
-		if err := v.validateFields(v.qb.fields); err != nil {
-			return "", nil, err
-		}
+	if err := v.validate(); err != nil {
+		return "", nil, err
 	}
 
-	// Validate filter (WHERE clause)
-	if v.qb.filter != nil && len(v.allowedFields) > 0 {
-		if err := v.validateFilter(v.qb.filter); err != nil {
-			return "", nil, err
-		}
+	// If all validations pass, build SQL
+	sql, args, err := v.resolved().ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// ToNamedSQL builds the SQL query with named arguments after validating all parameters.
+// Returns an error if any validation fails.
+func (v *Validator) ToNamedSQL() (string, []sql.NamedArg, error) {
+	if err := v.validate(); err != nil {
+		return "", nil, err
 	}
+	return v.resolved().ToNamedSQL()
+}
 
-	// Validate sort (ORDER BY clause)
-	if len(v.qb.sort) > 0 && len(v.allowedFields) > 0 {
-		if err := v.validateSort(v.qb.sort); err != nil {
+// ToTypedSQL builds the SQL query with typed arguments after validating all parameters.
+// Returns an error if any validation fails.
+func (v *Validator) ToTypedSQL() (string, []Arg, error) {
+	if err := v.validate(); err != nil {
+		return "", nil, err
+	}
+	return v.resolved().ToTypedSQL()
+}
+
+// ToSQLDebug builds the SQL query with placeholder-paired arguments after
+// validating all parameters. Returns an error if any validation fails.
+func (v *Validator) ToSQLDebug() (string, []DebugArg, error) {
+	if err := v.validate(); err != nil {
+		return "", nil, err
+	}
+	return v.resolved().ToSQLDebug()
+}
+
+// ToCountSQL builds the COUNT(*) query after validating all parameters.
+// Returns an error if any validation fails.
+func (v *Validator) ToCountSQL() (string, []any, error) {
+	if err := v.validate(); err != nil {
+		return "", nil, err
+	}
+	return v.resolved().ToCountSQL()
+}
+
+// ToCountDistinctSQL builds the COUNT(DISTINCT field) query after validating
+// all parameters and the counted field.
+// Returns an error if any validation fails.
+func (v *Validator) ToCountDistinctSQL(field string) (string, []any, error) {
+	if err := v.validate(); err != nil {
+		return "", nil, err
+	}
+	if v.hasFieldRules() {
+		canonical, err := v.checkField(field)
+		if err != nil {
 			return "", nil, err
 		}
+		field = canonical
 	}
+	return v.resolved().ToCountDistinctSQL(field)
+}
 
-	// Validate limit and offset
-	if err := v.validateLimitOffset(); err != nil {
-		return "", nil, err
+// ToSQLInline builds the SQL query with inlined literals after validating all
+// parameters. The result is for display only; see QueryBuilder.ToSQLInline.
+func (v *Validator) ToSQLInline() (string, error) {
+	if err := v.validate(); err != nil {
+		return "", err
 	}
+	return v.resolved().ToSQLInline()
+}
 
-	// If all validations pass, build SQL
-	sql, args, err := v.qb.ToSQL()
-	if err != nil {
+// ToSQLWithTimeout builds the SQL query and its timeout setup statement after
+// validating all parameters; see QueryBuilder.ToSQLWithTimeout.
+func (v *Validator) ToSQLWithTimeout(timeout time.Duration) (string, string, []any, error) {
+	if err := v.validate(); err != nil {
+		return "", "", nil, err
+	}
+	return v.resolved().ToSQLWithTimeout(timeout)
+}
+
+// ToSQLFor builds the SQL query for another dialect after validating all
+// parameters; see QueryBuilder.ToSQLFor.
+func (v *Validator) ToSQLFor(dialect Dialect) (string, []any, error) {
+	if err := v.validate(); err != nil {
 		return "", nil, err
 	}
-	return sql, args, nil
+	return v.resolved().ToSQLFor(dialect)
+}
+
+// Where builds the WHERE predicate after validating all parameters; see
+// QueryBuilder.Where. Unlike QueryBuilder.Where, build errors are reported.
+func (v *Validator) Where() (string, []any, bool, error) {
+	if err := v.validate(); err != nil {
+		return "", nil, false, err
+	}
+	qb := v.resolved()
+	clause, args, ok := qb.Where()
+	if qb.err != nil {
+		return "", nil, false, qb.err
+	}
+	return clause, args, ok, nil
+}
+
+// OrderBy builds the ORDER BY expressions after validating all parameters;
+// see QueryBuilder.OrderBy. Unlike QueryBuilder.OrderBy, invalid sort
+// expressions are reported instead of skipped.
+func (v *Validator) OrderBy() (string, error) {
+	if err := v.validate(); err != nil {
+		return "", err
+	}
+	qb := v.resolved()
+	qb.reset()
+	orderBy := qb.buildOrderBy()
+	if qb.err != nil {
+		return "", qb.err
+	}
+	return orderBy, nil
+}
+
+// Limit returns the requested limit, or 0 when none was set.
+func (v *Validator) Limit() int {
+	return v.qb.Limit()
+}
+
+// Offset returns the requested offset, or 0 when none was set.
+func (v *Validator) Offset() int {
+	return v.qb.Offset()
+}
+
+// Validate runs all configured validations and returns every violation
+// found, in clause order, so callers can report them together. It returns
+// nil when the query is valid.
+func (v *Validator) Validate() []error {
+	var errs []error
+
+	// Drop client conditions, fields and sorts on disallowed fields
+	if v.stripDisallowed && v.hasFieldRules() {
+		v.stripDisallowedFields()
+	}
+
+	// Report errors from building the filter in code
+	if err := v.qb.filter.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Require a filter (WHERE clause)
+	if v.requireFilter && !v.hasFilter() {
+		errs = append(errs, errors.New("at least one filter condition is required"))
+	}
+
+	// Limit the number of selected fields
+	if v.maxFields != nil && len(v.qb.fields) > *v.maxFields {
+		errs = append(errs, fmt.Errorf("%d fields requested exceeds maximum allowed of %d", len(v.qb.fields), *v.maxFields))
+	}
+
+	// Limit the number of sort fields
+	if v.maxSortFields != nil && len(v.qb.sort) > *v.maxSortFields {
+		errs = append(errs, fmt.Errorf("%d sort fields requested exceeds maximum allowed of %d", len(v.qb.sort), *v.maxSortFields))
+	}
+
+	if v.hasFieldRules() {
+		// Validate fields (SELECT clause)
+		errs = append(errs, v.validateFields(v.qb.fields)...)
+		errs = append(errs, v.validateFields(v.qb.ensuredFields)...)
+		errs = append(errs, v.validateFields(v.qb.distinctOn)...)
+
+		// Validate group by fields (GROUP BY clause)
+		errs = append(errs, v.validateFields(v.qb.groupBy)...)
+
+		// Validate filter (WHERE clause)
+		errs = append(errs, v.validateFilter(v.qb.filter)...)
+
+		// Validate IN-subquery fields (WHERE clause)
+		errs = append(errs, v.validateSubqueries(v.qb.inSubqueries)...)
+
+		// Validate IN-condition fields (WHERE clause)
+		errs = append(errs, v.validateInConditions(v.qb.inConditions)...)
+
+		// Validate sort (ORDER BY clause)
+		errs = append(errs, v.validateSort(v.qb.sort)...)
+
+		// Validate default sort (ORDER BY tiebreaker)
+		errs = append(errs, v.validateSort(v.qb.defaultSort)...)
+	}
+
+	// Handle repeated sort fields
+	if err := v.validateSortDuplicates(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Limit the number of bound arguments
+	if v.maxArgs != nil {
+		if n := v.qb.ArgCount(); n > *v.maxArgs {
+			errs = append(errs, fmt.Errorf("%d query arguments exceed maximum allowed of %d", n, *v.maxArgs))
+		}
+	}
+
+	// Validate limit and offset
+	return append(errs, v.validateLimitOffset()...)
+}
+
+// validate runs all configured validations and returns the first violation.
+// Rejections are logged at warn level when the query has a logger.
+func (v *Validator) validate() error {
+	errs := v.Validate()
+	if len(errs) == 0 {
+		return nil
+	}
+	if v.qb.logger != nil {
+		v.qb.logger.Warn("restql: query rejected", "table", v.qb.table, "error", errors.Join(errs...))
+	}
+	return errs[0]
+}
+
+// hasFilter reports whether the query has at least one filter condition.
+func (v *Validator) hasFilter() bool {
+	filter := v.qb.filter
+	return filter != nil && filter.Expression != nil && len(filter.Expression.And) > 0
 }
 
 // validateFields validates that all fields in the slice are allowed.
-func (v *Validator) validateFields(fields []string) error {
+// For aggregates such as "COUNT(id) AS total" only the inner field is checked.
+func (v *Validator) validateFields(fields []string) []error {
+	var errs []error
 	for _, field := range fields {
-		if !v.isFieldAllowed(field) {
-			return fmt.Errorf("field '%s' is not allowed. Allowed fields: %v", field, v.allowedFieldsList())
+		if _, err := v.checkSelect(field); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return nil
+	return errs
 }
 
 // validateFilter validates all fields used in the filter AST.
-func (v *Validator) validateFilter(filter *parser.Filter) error {
+func (v *Validator) validateFilter(filter *parser.Filter) []error {
 	if filter == nil || filter.Expression == nil {
 		return nil
 	}
 	return v.validateOrExpr(filter.Expression)
 }
 
-// validateSort validates the sort fields.
-func (v *Validator) validateSort(sort []string) error {
+// validateSubqueries validates the left field of each IN-subquery.
+// The subquery itself is written by the caller and is not checked.
+func (v *Validator) validateSubqueries(subqueries []inSubquery) []error {
+	var errs []error
+	for _, subquery := range subqueries {
+		if _, err := v.checkField(subquery.field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateInConditions validates the field of each IN-condition.
+func (v *Validator) validateInConditions(conditions []inCondition) []error {
+	var errs []error
+	for _, condition := range conditions {
+		if _, err := v.checkField(condition.field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateSort validates the sort fields, including the columns used inside
+// sort functions (e.g. "-COALESCE(updated_at,created_at)").
+func (v *Validator) validateSort(sort []string) []error {
+	var errs []error
 	for _, sortField := range sort {
-		// Extract field name (remove - prefix if present)
-		field := strings.TrimPrefix(sortField, "-")
+		expr, err := parseSort(sortField)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, field := range expr.fields {
+			if _, err := v.checkField(field); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
 
-		if !v.isFieldAllowed(field) {
-			return fmt.Errorf("field '%s' is not allowed. Allowed fields: %v", field, v.allowedFieldsList())
+// validateSortDuplicates rejects or drops repeated client sort fields
+// (e.g. "name,-name") according to the configured SortDuplicates mode.
+func (v *Validator) validateSortDuplicates() error {
+	if v.sortDuplicates == SortDuplicatesAllow {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(v.qb.sort))
+	sort := make([]string, 0, len(v.qb.sort))
+	for _, s := range v.qb.sort {
+		key := sortKey(s)
+		if !seen[key] {
+			seen[key] = true
+			sort = append(sort, s)
+			continue
+		}
+		if v.sortDuplicates == SortDuplicatesError {
+			return fmt.Errorf("duplicate sort field '%s'", key)
 		}
 	}
+	v.qb.sort = sort
 	return nil
 }
 
-// validateLimitOffset validates limit and offset against configured maximums.
-func (v *Validator) validateLimitOffset() error {
+// stripDisallowedFields removes selected fields, sorts and filter comparisons
+// that reference disallowed fields, pruning AND/OR groups left empty.
+// IN-subqueries and IN-conditions are set by the server and are still
+// reported as errors. The filter is replaced by a stripped copy, so the
+// caller's AST is left unchanged.
+func (v *Validator) stripDisallowedFields() {
+	v.qb.fields = slices.DeleteFunc(slices.Clone(v.qb.fields), func(field string) bool {
+		_, err := v.checkSelect(field)
+		return err != nil
+	})
+	v.qb.sort = v.stripSort(v.qb.sort)
+
+	if filter := v.qb.filter; filter != nil && filter.Expression != nil {
+		if expr := v.stripOrExpr(filter.Expression); expr != nil {
+			v.qb.filter = &parser.Filter{Expression: expr}
+		} else {
+			v.qb.filter = nil
+		}
+	}
+}
+
+// stripSort removes sort entries that reference disallowed fields. Entries
+// that don't parse are kept so that validateSort reports them.
+func (v *Validator) stripSort(sort []string) []string {
+	return slices.DeleteFunc(slices.Clone(sort), func(s string) bool {
+		expr, err := parseSort(s)
+		if err != nil {
+			return false
+		}
+		for _, field := range expr.fields {
+			if _, err := v.checkField(field); err != nil {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// stripOrExpr returns a copy of expr without the comparisons that reference
+// disallowed fields and the AND groups left empty, or nil if no condition
+// remains.
+func (v *Validator) stripOrExpr(expr *parser.OrExpr) *parser.OrExpr {
+	and := make([]*parser.AndExpr, 0, len(expr.And))
+	for _, andExpr := range expr.And {
+		if andExpr == nil {
+			continue
+		}
+		comparisons := make([]*parser.Comparison, 0, len(andExpr.Comparison))
+		for _, comp := range andExpr.Comparison {
+			if comp = v.stripComparison(comp); comp != nil {
+				comparisons = append(comparisons, comp)
+			}
+		}
+		if len(comparisons) > 0 {
+			and = append(and, &parser.AndExpr{Comparison: comparisons})
+		}
+	}
+	if len(and) == 0 {
+		return nil
+	}
+	return &parser.OrExpr{And: and}
+}
+
+// stripComparison returns the comparison to keep, or nil: a comparison is
+// kept if it references only allowed fields, and a group is copied with its
+// remaining conditions if any remain.
+func (v *Validator) stripComparison(comp *parser.Comparison) *parser.Comparison {
+	if comp == nil || comp.Left == nil {
+		return nil
+	}
+	if comp.Left.SubExpr != nil {
+		expr := v.stripOrExpr(comp.Left.SubExpr)
+		if expr == nil {
+			return nil
+		}
+		left := *comp.Left
+		left.SubExpr = expr
+		stripped := *comp
+		stripped.Left = &left
+		return &stripped
+	}
+	for _, field := range comp.Left.Fields() {
+		if _, err := v.checkField(strings.TrimSpace(field)); err != nil {
+			return nil
+		}
+	}
+	return comp
+}
+
+// validateLimitOffset rejects negative limit and offset and validates them
+// against configured maximums. LimitAll is accepted only with WithUnboundedLimit.
+func (v *Validator) validateLimitOffset() []error {
+	var errs []error
+
+	switch {
+	case v.qb.limit == LimitAll && !v.unboundedLimit:
+		errs = append(errs, errors.New("unbounded limit is not allowed"))
+	case v.qb.limit < 0 && v.qb.limit != LimitAll:
+		errs = append(errs, fmt.Errorf("limit %d must not be negative", v.qb.limit))
+	}
+
+	if v.qb.offset < 0 {
+		errs = append(errs, fmt.Errorf("offset %d must not be negative", v.qb.offset))
+	}
+
 	if v.maxLimit != nil && v.qb.limit > *v.maxLimit {
-		return fmt.Errorf("limit %d exceeds maximum allowed limit of %d", v.qb.limit, *v.maxLimit)
+		errs = append(errs, fmt.Errorf("limit %d exceeds maximum allowed limit of %d", v.qb.limit, *v.maxLimit))
 	}
 
 	if v.maxOffset != nil && v.qb.offset > *v.maxOffset {
-		return fmt.Errorf("offset %d exceeds maximum allowed offset of %d", v.qb.offset, *v.maxOffset)
+		errs = append(errs, fmt.Errorf("offset %d exceeds maximum allowed offset of %d", v.qb.offset, *v.maxOffset))
 	}
 
-	return nil
+	return errs
 }
 
 // validateOrExpr validates OR expressions recursively.
-func (v *Validator) validateOrExpr(expr *parser.OrExpr) error {
+func (v *Validator) validateOrExpr(expr *parser.OrExpr) []error {
 	if expr == nil {
 		return nil
 	}
+	var errs []error
 	for _, andExpr := range expr.And {
-		if err := v.validateAndExpr(andExpr); err != nil {
-			return err
-		}
+		errs = append(errs, v.validateAndExpr(andExpr)...)
 	}
-	return nil
+	return errs
 }
 
 // validateAndExpr validates AND expressions recursively.
-func (v *Validator) validateAndExpr(expr *parser.AndExpr) error {
+func (v *Validator) validateAndExpr(expr *parser.AndExpr) []error {
 	if expr == nil {
 		return nil
 	}
+	var errs []error
 	for _, comp := range expr.Comparison {
-		if err := v.validateComparison(comp); err != nil {
-			return err
-		}
+		errs = append(errs, v.validateComparison(comp)...)
 	}
-	return nil
+	return errs
 }
 
 // validateComparison validates a comparison expression.
-func (v *Validator) validateComparison(comp *parser.Comparison) error {
-	if comp == nil {
+func (v *Validator) validateComparison(comp *parser.Comparison) []error {
+	if comp == nil || comp.Left == nil {
 		return nil
 	}
 
-	if comp.Left == nil {
-		return nil
-	}
+	var errs []error
 
-	// Validate field name
+	// Validate field name and operator
 	if comp.Left.Field != "" {
 		field := strings.TrimSpace(comp.Left.Field)
-		if !v.isFieldAllowed(field) {
-			return fmt.Errorf("field '%s' is not allowed. Allowed fields: %v", field, v.allowedFieldsList())
+		if err := v.checkOperator(field, comp.Op); err != nil {
+			errs = append(errs, err)
+		}
+		errs = append(errs, v.checkValues(field, comp)...)
+		if _, err := v.checkField(field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Validate fields used in arithmetic on the left side
+	for _, a := range comp.Left.Arith {
+		if a == nil || a.Field == "" {
+			continue
+		}
+		if _, err := v.checkField(a.Field); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
 	// Validate subexpression if present
 	if comp.Left.SubExpr != nil {
-		return v.validateOrExpr(comp.Left.SubExpr)
+		errs = append(errs, v.validateOrExpr(comp.Left.SubExpr)...)
 	}
 
+	return errs
+}
+
+// checkOperator validates the operator used with a field against the globally
+// allowed and forbidden operators and those allowed for the field. Bare
+// predicates have no operator and pass.
+func (v *Validator) checkOperator(field string, op *parser.Operator) error {
+	if op == nil {
+		return nil
+	}
+	name := operatorName(op.ResolveTilde(v.qb.tildeILike))
+	if v.forbiddenOperators[name] || len(v.allowedOperators) > 0 && !v.allowedOperators[name] {
+		return fmt.Errorf("operator '%s' is not allowed", name)
+	}
+
+	allowed, ok := v.fieldRule(v.fieldOperators, field)
+	if !ok {
+		return nil
+	}
+	if !allowed[name] {
+		return fmt.Errorf("operator '%s' is not allowed for field '%s'", name, field)
+	}
 	return nil
 }
 
+// checkValues validates the values a field is compared with against those
+// allowed for the field. Pattern operators and null checks pass.
+func (v *Validator) checkValues(field string, comp *parser.Comparison) []error {
+	if len(v.allowedValues) == 0 || comp.Op == nil {
+		return nil
+	}
+	switch op := comp.Op; {
+	case op.Like, op.NotLike, op.ILike, op.NotILike, op.Regexp, op.NotRegexp, op.Tilde, op.NotTilde:
+		return nil
+	}
+	allowed, ok := v.fieldRule(v.allowedValues, field)
+	if !ok {
+		return nil
+	}
+
+	values := []*parser.Value{comp.Right, comp.To}
+	if comp.Right != nil && comp.Right.Array != nil {
+		values = comp.Right.Array.Values
+	}
+
+	var errs []error
+	for _, val := range values {
+		if val == nil || val.Null {
+			continue
+		}
+		resolved, _ := val.Resolve()
+		if text := fmt.Sprint(resolved); !allowed[text] {
+			errs = append(errs, fmt.Errorf("value '%s' is not allowed for field '%s'", text, field))
+		}
+	}
+	return errs
+}
+
+// fieldRule returns the rule registered for field in rules. The field is
+// matched by the name checkField resolves it from: converted to snake case,
+// without a base-table qualifier and, with case-insensitive matching, in any
+// case.
+func (v *Validator) fieldRule(rules map[string]map[string]bool, field string) (map[string]bool, bool) {
+	if len(rules) == 0 {
+		return nil, false
+	}
+	if v.snakeCase {
+		field = toSnakeCase(field)
+	}
+	if name, ok := v.unqualify(field); ok {
+		field = name
+	}
+	if rule, ok := rules[field]; ok {
+		return rule, true
+	}
+	if v.caseInsensitive {
+		for name, rule := range rules {
+			if strings.EqualFold(name, field) {
+				return rule, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// operatorName returns the name of an operator as accepted by
+// WithFieldOperators, e.g. "=", "LIKE" or "CONTAINS".
+func operatorName(op *parser.Operator) string {
+	if op.Contains {
+		return "CONTAINS"
+	}
+	return op.String()
+}
+
 // isFieldAllowed checks if a field is in the whitelist.
+// A field qualified with the base table or its alias (e.g. "users.id") is
+// allowed when its unqualified name is, and a JSON path (e.g. "data.country")
+// when its JSON column is.
 func (v *Validator) isFieldAllowed(field string) bool {
 	if len(v.allowedFields) == 0 {
 		// If no allowed fields are configured, allow all
 		return true
 	}
-	return v.allowedFields[field]
+	if v.allowedFields[field] {
+		return true
+	}
+	if column, _, ok := v.qb.jsonPath(field); ok {
+		return v.allowedFields[column]
+	}
+	if name, ok := v.unqualify(field); ok {
+		return v.allowedFields[name]
+	}
+	return false
+}
+
+// unqualify strips a base table or table alias qualifier from a field.
+func (v *Validator) unqualify(field string) (string, bool) {
+	if name, ok := strings.CutPrefix(field, v.qb.table+"."); ok {
+		return name, true
+	}
+	if v.qb.tableAlias != "" {
+		return strings.CutPrefix(field, v.qb.tableAlias+".")
+	}
+	return "", false
+}
+
+// hasFieldRules reports whether any field allowlist, denylist, operator
+// rule, column mapper or snake-case conversion is configured.
+func (v *Validator) hasFieldRules() bool {
+	return len(v.allowedFields) > 0 || len(v.forbiddenFields) > 0 || len(v.fieldOperators) > 0 ||
+		len(v.allowedOperators) > 0 || len(v.forbiddenOperators) > 0 || len(v.allowedValues) > 0 ||
+		v.columnMapper != nil || v.snakeCase
+}
+
+// checkSelect checks a selected field, which may be an aggregate over a
+// field, and returns it with the field in canonical form.
+func (v *Validator) checkSelect(field string) (string, error) {
+	e, err := parseSelect(field)
+	if err != nil {
+		return "", err
+	}
+	if e.field == "*" {
+		return e.String(), nil
+	}
+	if e.field, err = v.checkField(e.field); err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+// checkField validates a field against the forbidden and allowed fields and
+// returns its canonical name. Forbidden fields are rejected even if allowed.
+// With snake-case fields, the field is converted before matching; with a
+// column mapper, the canonical name is the mapped column.
+func (v *Validator) checkField(field string) (string, error) {
+	name := field
+	if v.snakeCase {
+		name = toSnakeCase(field)
+	}
+	if v.isFieldForbidden(name) {
+		return "", fmt.Errorf("field '%s' is forbidden", field)
+	}
+	canonical, ok := v.resolveField(name)
+	if !ok {
+		return "", fmt.Errorf("field '%s' is not allowed. Allowed fields: %v", field, v.allowedFieldsList())
+	}
+	if v.columnMapper != nil {
+		column, ok := v.columnMapper(canonical)
+		if !ok {
+			return "", fmt.Errorf("field '%s' is not allowed", field)
+		}
+		canonical = column
+	}
+	return canonical, nil
+}
+
+// isFieldForbidden checks if a field is in the blacklist, ignoring case and a
+// base-table qualifier (e.g. "users.ssn"), so that a blocked field can't be
+// reached by spelling it differently.
+func (v *Validator) isFieldForbidden(field string) bool {
+	if column, _, ok := v.qb.jsonPath(field); ok && v.forbiddenFields[strings.ToLower(column)] {
+		return true
+	}
+	if name, ok := v.unqualify(field); ok {
+		field = name
+	}
+	return v.forbiddenFields[strings.ToLower(field)]
+}
+
+// resolveField checks if a field is allowed and returns its canonical name.
+// With case-insensitive matching, the canonical name is the registered casing.
+func (v *Validator) resolveField(field string) (string, bool) {
+	if v.isFieldAllowed(field) {
+		return field, true
+	}
+	if v.caseInsensitive {
+		for allowed := range v.allowedFields {
+			if strings.EqualFold(allowed, field) {
+				return allowed, true
+			}
+		}
+	}
+	return "", false
+}
+
+// resolved returns the builder to emit SQL from. When field names are
+// translated (case-insensitive matching, snake case or a column mapper), it
+// is a copy whose fields, sorts and filter use the canonical columns; the
+// builder itself keeps the names as sent, so validating again checks them.
+func (v *Validator) resolved() *QueryBuilder {
+	if !v.caseInsensitive && !v.snakeCase && v.columnMapper == nil {
+		return v.qb
+	}
+
+	qb := *v.qb
+	qb.fields = v.canonicalSelects(qb.fields)
+	qb.ensuredFields = v.canonicalSelects(qb.ensuredFields)
+	qb.distinctOn = v.canonicalSelects(qb.distinctOn)
+	qb.groupBy = v.canonicalSelects(qb.groupBy)
+	qb.sort = v.canonicalSorts(qb.sort)
+	qb.defaultSort = v.canonicalSorts(qb.defaultSort)
+	if qb.filter != nil {
+		qb.filter = &parser.Filter{Expression: v.canonicalOrExpr(qb.filter.Expression)}
+	}
+	qb.inSubqueries = slices.Clone(qb.inSubqueries)
+	for i := range qb.inSubqueries {
+		qb.inSubqueries[i].field = v.canonicalField(qb.inSubqueries[i].field)
+	}
+	qb.inConditions = slices.Clone(qb.inConditions)
+	for i := range qb.inConditions {
+		qb.inConditions[i].field = v.canonicalField(qb.inConditions[i].field)
+	}
+	return &qb
+}
+
+// canonicalField returns the canonical name of a validated field, or the
+// field unchanged when it doesn't resolve.
+func (v *Validator) canonicalField(field string) string {
+	canonical, err := v.checkField(strings.TrimSpace(field))
+	if err != nil {
+		return field
+	}
+	return canonical
+}
+
+// canonicalSelects returns a copy of selected fields with canonical names.
+func (v *Validator) canonicalSelects(fields []string) []string {
+	if fields == nil {
+		return nil
+	}
+	out := make([]string, len(fields))
+	for i, field := range fields {
+		canonical, err := v.checkSelect(field)
+		if err != nil {
+			canonical = field
+		}
+		out[i] = canonical
+	}
+	return out
+}
+
+// canonicalSorts returns a copy of sort expressions with canonical names.
+func (v *Validator) canonicalSorts(sort []string) []string {
+	if sort == nil {
+		return nil
+	}
+	out := make([]string, len(sort))
+	for i, sortField := range sort {
+		expr, err := parseSort(sortField)
+		if err != nil {
+			out[i] = sortField
+			continue
+		}
+		for j, field := range expr.fields {
+			expr.fields[j] = v.canonicalField(field)
+		}
+		out[i] = expr.String()
+	}
+	return out
+}
+
+// canonicalOrExpr returns a copy of expr with canonical field names.
+func (v *Validator) canonicalOrExpr(expr *parser.OrExpr) *parser.OrExpr {
+	if expr == nil {
+		return nil
+	}
+	out := &parser.OrExpr{And: make([]*parser.AndExpr, len(expr.And))}
+	for i, andExpr := range expr.And {
+		if andExpr == nil {
+			continue
+		}
+		comparisons := make([]*parser.Comparison, len(andExpr.Comparison))
+		for j, comp := range andExpr.Comparison {
+			comparisons[j] = v.canonicalComparison(comp)
+		}
+		out.And[i] = &parser.AndExpr{Comparison: comparisons}
+	}
+	return out
+}
+
+// canonicalComparison returns a copy of comp with canonical field names.
+// Values are shared with the original.
+func (v *Validator) canonicalComparison(comp *parser.Comparison) *parser.Comparison {
+	if comp == nil || comp.Left == nil {
+		return comp
+	}
+
+	left := *comp.Left
+	if left.Field != "" {
+		left.Field = v.canonicalField(left.Field)
+	}
+	if left.Arith != nil {
+		left.Arith = make([]*parser.Arithmetic, len(comp.Left.Arith))
+		for i, a := range comp.Left.Arith {
+			if a != nil && a.Field != "" {
+				resolved := *a
+				resolved.Field = v.canonicalField(a.Field)
+				a = &resolved
+			}
+			left.Arith[i] = a
+		}
+	}
+	left.SubExpr = v.canonicalOrExpr(comp.Left.SubExpr)
+
+	resolved := *comp
+	resolved.Left = &left
+	return &resolved
 }
 
 // allowedFieldsList returns all allowed fields as a slice for error messages.
@@ -165,3 +869,37 @@ func (v *Validator) allowedFieldsList() []string {
 	}
 	return fields
 }
+
+// toSnakeCase converts a camelCase field to snake_case, keeping acronyms
+// together: "createdAt" becomes "created_at" and "userID" becomes "user_id".
+func toSnakeCase(field string) string {
+	var out strings.Builder
+	out.Grow(len(field) + 4)
+
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if !isUpper(c) {
+			out.WriteByte(c)
+			continue
+		}
+		if i > 0 {
+			prev := field[i-1]
+			nextLower := i+1 < len(field) && isLower(field[i+1])
+			if isLower(prev) || isDigit(prev) || (isUpper(prev) && nextLower) {
+				out.WriteByte('_')
+			}
+		}
+		out.WriteByte(c + 'a' - 'A')
+	}
+	return out.String()
+}
+
+// isUpper reports whether c is an ASCII uppercase letter.
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+// isLower reports whether c is an ASCII lowercase letter.
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
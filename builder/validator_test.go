@@ -1,7 +1,9 @@
 package builder
 
 import (
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -223,6 +225,91 @@ func TestValidator_MaxOffset(t *testing.T) {
 	})
 }
 
+func TestValidator_MaxFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("field count at max succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"id", "name", "email"})
+
+		sql, _, err := qb.Validate(WithMaxFields(3)).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name, email FROM users", sql)
+	})
+
+	t.Run("field count over max fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"id", "name", "email", "age"})
+
+		_, _, err := qb.Validate(WithMaxFields(3)).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "4 fields requested exceeds maximum allowed of 3", err.Error())
+	})
+}
+
+func TestValidator_MaxSortFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sort count at max succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-created_at", "name"})
+		qb.SetDefaultSort("id")
+
+		sql, _, err := qb.Validate(WithMaxSortFields(2)).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY created_at DESC, name ASC, id ASC", sql)
+	})
+
+	t.Run("sort count over max fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"a", "b", "c"})
+
+		_, _, err := qb.Validate(WithMaxSortFields(2)).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "3 sort fields requested exceeds maximum allowed of 2", err.Error())
+	})
+}
+
+func TestValidator_NegativePagination(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negative limit fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetLimit(-5)
+
+		_, _, err := qb.Validate().ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "limit -5 must not be negative", err.Error())
+	})
+
+	t.Run("negative offset fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetOffset(-1)
+
+		_, _, err := qb.Validate(WithMaxOffset(1000)).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "offset -1 must not be negative", err.Error())
+	})
+}
+
 func TestValidator_Combined(t *testing.T) {
 	t.Parallel()
 
@@ -328,3 +415,1439 @@ func TestValidator_ComplexFilter(t *testing.T) {
 		assert.Contains(t, err.Error(), "password")
 	})
 }
+
+func TestValidator_DefaultSort(t *testing.T) {
+	t.Parallel()
+
+	t.Run("client sort gets tiebreaker appended", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-created_at"})
+
+		sql, _, err := qb.Validate(
+			WithAllowedFields([]string{"id", "created_at"}),
+			WithDefaultSort("id"),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY created_at DESC, id ASC", sql)
+	})
+
+	t.Run("tiebreaker already in client sort is not duplicated", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-id", "name"})
+
+		sql, _, err := qb.Validate(WithDefaultSort("id")).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY id DESC, name ASC", sql)
+	})
+
+	t.Run("no client sort uses default", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+
+		sql, _, err := qb.Validate(WithDefaultSort("-created_at", "id")).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY created_at DESC, id ASC", sql)
+	})
+
+	t.Run("default sort fields are validated", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"name"})
+
+		_, _, err := qb.Validate(
+			WithAllowedFields([]string{"name"}),
+			WithDefaultSort("id"),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'id' is not allowed")
+	})
+}
+
+func TestValidator_CaseInsensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("filter field matches and emits canonical casing", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("Status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.Validate(
+			WithAllowedFields([]string{"status"}),
+			WithCaseInsensitiveFields(),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE status = ?", sql)
+		assert.Equal(t, []any{"active"}, args)
+	})
+
+	t.Run("fields and sort emit canonical casing", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"ID", "Name"})
+		qb.SetSort([]string{"-CreatedAt"})
+
+		sql, _, err := qb.Validate(
+			WithAllowedFields([]string{"id", "name", "createdAt"}),
+			WithCaseInsensitiveFields(),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name FROM users ORDER BY createdAt DESC", sql)
+	})
+
+	t.Run("case mismatch fails without option", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("Status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(
+			WithAllowedFields([]string{"status"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'Status' is not allowed")
+	})
+}
+
+func TestValidator_InnerJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("qualified field of joined table", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("profiles.verified=true && users.age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.InnerJoin("profiles", "users.id = profiles.user_id")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"users.id", "profiles.bio"})
+
+		sql, args, err := qb.Validate(
+			WithAllowedFields([]string{"id", "age", "profiles.verified", "profiles.bio"}),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT users.id, profiles.bio FROM users INNER JOIN profiles ON users.id = profiles.user_id WHERE (profiles.verified = ? AND users.age > ?)", sql)
+		assert.Equal(t, []any{true, 18}, args)
+	})
+
+	t.Run("unlisted field of joined table fails", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("profiles.ssn='123'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.InnerJoin("profiles", "users.id = profiles.user_id")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(
+			WithAllowedFields([]string{"id", "profiles.verified"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'profiles.ssn' is not allowed")
+	})
+}
+
+func TestValidator_ForbiddenFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("blocked field in filter fails", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && ssn='123'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(WithForbiddenFields([]string{"password_hash", "ssn"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'ssn' is forbidden")
+	})
+
+	t.Run("blocked field in fields fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"id", "password_hash"})
+
+		_, _, err := qb.Validate(WithForbiddenFields([]string{"password_hash", "ssn"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password_hash' is forbidden")
+	})
+
+	t.Run("blocked field in sort fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-ssn"})
+
+		_, _, err := qb.Validate(WithForbiddenFields([]string{"password_hash", "ssn"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'ssn' is forbidden")
+	})
+
+	t.Run("other fields pass through", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"id", "name"})
+		qb.SetSort([]string{"-created_at"})
+
+		sql, args, err := qb.Validate(WithForbiddenFields([]string{"password_hash", "ssn"})).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name FROM users WHERE age > ? ORDER BY created_at DESC", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("deny wins over allow", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"id", "ssn"})
+
+		_, _, err := qb.Validate(
+			WithAllowedFields([]string{"id", "ssn"}),
+			WithForbiddenFields([]string{"ssn"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'ssn' is forbidden")
+	})
+
+	t.Run("table-qualified blocked field fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"users.ssn"})
+
+		_, _, err := qb.Validate(WithForbiddenFields([]string{"ssn"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'users.ssn' is forbidden")
+	})
+
+	t.Run("blocked field in another case fails", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("SSN='1'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(WithForbiddenFields([]string{"ssn"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'SSN' is forbidden")
+	})
+
+	t.Run("table-qualified blocked field in filter fails", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("users.ssn='1'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(WithForbiddenFields([]string{"ssn"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'users.ssn' is forbidden")
+	})
+}
+
+func TestValidator_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("arithmetic operand fields are validated", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("price * cost > 1000")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("products")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(WithAllowedFields([]string{"price", "quantity"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'cost' is not allowed")
+	})
+
+	t.Run("allowed arithmetic operand fields pass", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("price * quantity > 1000")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("products")
+		qb.SetFilter(filter)
+
+		sql, _, err := qb.Validate(WithAllowedFields([]string{"price", "quantity"})).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM products WHERE price * quantity > ?", sql)
+	})
+}
+
+func TestValidator_RequireFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty filter fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("events")
+		qb.SetLimit(10)
+
+		_, _, err := qb.Validate(WithRequireFilter()).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at least one filter condition is required")
+	})
+
+	t.Run("present filter succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("tenant_id=7")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("events")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.Validate(WithRequireFilter()).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM events WHERE tenant_id = ?", sql)
+		assert.Equal(t, []any{7}, args)
+	})
+}
+
+func TestValidator_SortFunctions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("COALESCE descending with both columns allowed", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("posts")
+		qb.SetSort([]string{"-COALESCE(updated_at,created_at)", "id"})
+
+		sql, _, err := qb.Validate(
+			WithAllowedFields([]string{"id", "updated_at", "created_at"}),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM posts ORDER BY COALESCE(updated_at, created_at) DESC, id ASC", sql)
+	})
+
+	t.Run("inner column not allowed fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("posts")
+		qb.SetSort([]string{"-COALESCE(updated_at,secret)"})
+
+		_, _, err := qb.Validate(
+			WithAllowedFields([]string{"updated_at", "created_at"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'secret' is not allowed")
+	})
+
+	t.Run("function outside the whitelist fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("posts")
+		qb.SetSort([]string{"pg_sleep(10)"})
+
+		_, _, err := qb.Validate().ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "function 'pg_sleep' is not allowed in sort")
+	})
+
+	t.Run("non-column argument fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("posts")
+		qb.SetSort([]string{"LOWER(name || 'x')"})
+
+		_, _, err := qb.ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid sort expression")
+	})
+}
+
+func TestValidator_SortOrders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("custom sort field is canonicalized", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"-Status:custom"})
+
+		sql, _, err := qb.Validate(
+			WithAllowedFields([]string{"status"}),
+			WithCaseInsensitiveFields(),
+			WithSortOrders(map[string][]string{"status": {"active", "pending"}}),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY CASE status WHEN 'active' THEN 0 WHEN 'pending' THEN 1 ELSE 2 END DESC", sql)
+	})
+
+	t.Run("custom sort field not allowed fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"secret:custom"})
+
+		_, _, err := qb.Validate(
+			WithAllowedFields([]string{"status"}),
+			WithSortOrders(map[string][]string{"secret": {"a"}}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'secret' is not allowed")
+	})
+}
+
+func TestValidator_TableAlias(t *testing.T) {
+	t.Parallel()
+
+	t.Run("alias-qualified and unqualified fields are validated", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("u.age>18 && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, _, err := qb.Validate(
+			WithTableAlias("u"),
+			WithAllowedFields([]string{"age", "status"}),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users AS u WHERE (u.age > ? AND u.status = ?)", sql)
+	})
+
+	t.Run("alias-qualified disallowed field fails", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("u.password='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(
+			WithTableAlias("u"),
+			WithAllowedFields([]string{"age"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'u.password' is not allowed")
+	})
+}
+
+func TestValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports all violations at once", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && password='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"id", "ssn"})
+		qb.SetLimit(500)
+
+		errs := qb.Validate(
+			WithAllowedFields([]string{"id", "age"}),
+			WithMaxLimit(100),
+		).Validate()
+
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "field 'ssn' is not allowed")
+		assert.Contains(t, errs[1].Error(), "field 'password' is not allowed")
+		assert.Equal(t, "limit 500 exceeds maximum allowed limit of 100", errs[2].Error())
+	})
+
+	t.Run("valid query returns nil", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		errs := qb.Validate(WithAllowedFields([]string{"age"})).Validate()
+
+		assert.Nil(t, errs)
+	})
+
+	t.Run("ToSQL returns the first violation", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"ssn", "password"})
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"id"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'ssn' is not allowed")
+	})
+}
+
+func TestValidator_InSubquery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allowed left field passes", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetInSubquery("id", "SELECT user_id FROM admins")
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"id"})).ToSQL()
+
+		require.NoError(t, err)
+	})
+
+	t.Run("disallowed left field fails", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetInSubquery("ssn", "SELECT ssn FROM leaked")
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"id"})).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'ssn' is not allowed")
+	})
+}
+
+func TestValidator_SharedOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mutating the source list does not affect the option", func(t *testing.T) {
+		t.Parallel()
+
+		fields := []string{"id", "name"}
+		base := []ValidateOption{WithAllowedFields(fields)}
+		fields[1] = "password"
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"password"})
+
+		_, _, err := qb.Validate(base...).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+
+	t.Run("forbidden fields are copied too", func(t *testing.T) {
+		t.Parallel()
+
+		fields := []string{"ssn"}
+		base := []ValidateOption{WithForbiddenFields(fields)}
+		fields[0] = "id"
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"ssn"})
+
+		_, _, err := qb.Validate(base...).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'ssn' is forbidden")
+	})
+}
+
+func TestValidator_ColumnMapper(t *testing.T) {
+	t.Parallel()
+
+	snakeCase := func(field string) (string, bool) {
+		var b strings.Builder
+		for i, r := range field {
+			if unicode.IsUpper(r) {
+				if i > 0 {
+					b.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		return b.String(), true
+	}
+
+	t.Run("snake_case converter rewrites fields", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("firstName='john' && createdAt>2024-01-01")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"id", "firstName"})
+		qb.SetSort([]string{"-createdAt"})
+
+		validator := qb.Validate(WithColumnMapper(snakeCase))
+
+		sql, _, err := validator.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, first_name FROM users WHERE (first_name = ? AND created_at > ?) ORDER BY created_at DESC", sql)
+
+		// A second build does not re-map or reject mapped columns
+		again, _, err := validator.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, sql, again)
+	})
+
+	t.Run("mapper rejects unknown fields", func(t *testing.T) {
+		t.Parallel()
+
+		columns := map[string]string{"firstName": "first_name", "age": "age"}
+		mapper := func(field string) (string, bool) {
+			column, ok := columns[field]
+			return column, ok
+		}
+
+		filter, err := parser.ParseFilter("age>18 && passwordHash='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(WithColumnMapper(mapper)).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "field 'passwordHash' is not allowed", err.Error())
+	})
+
+	t.Run("composes with the allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"firstName", "lastName"})
+
+		_, _, err := qb.Validate(
+			WithAllowedFields([]string{"firstName"}),
+			WithColumnMapper(snakeCase),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'lastName' is not allowed")
+	})
+
+	t.Run("mapped columns are not allowed by themselves", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("first_name='john'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"firstName"})
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(
+			WithAllowedFields([]string{"firstName"}),
+			WithColumnMapper(snakeCase),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'first_name' is not allowed")
+	})
+
+	t.Run("validation leaves the builder unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("firstName='john' && price*taxRate>10")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"firstName"})
+		qb.SetSort([]string{"-createdAt"})
+		qb.SetFilter(filter)
+
+		sql, _, err := qb.Validate(WithColumnMapper(snakeCase)).ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT first_name FROM users WHERE (first_name = ? AND price * tax_rate > ?) ORDER BY created_at DESC", sql)
+
+		comp := filter.Expression.And[0].Comparison[0]
+		assert.Equal(t, "firstName", comp.Left.Field)
+		assert.Equal(t, "taxRate", filter.Expression.And[0].Comparison[1].Left.Arith[0].Field)
+
+		// A later validation checks the names as sent
+		_, _, err = qb.Validate(
+			WithForbiddenFields([]string{"firstName"}),
+			WithColumnMapper(snakeCase),
+		).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'firstName' is forbidden")
+	})
+}
+
+func TestValidator_FieldTypes(t *testing.T) {
+	t.Parallel()
+
+	types := map[string]parser.ValueKind{
+		"age":   parser.KindInt,
+		"score": parser.KindFloat,
+		"name":  parser.KindString,
+	}
+
+	testCases := []struct {
+		name         string
+		filter       string
+		expectedArgs []any
+	}{
+		{"quoted int is coerced", "age='18'", []any{18}},
+		{"quoted float is coerced", "score>='4.5'", []any{4.5}},
+		{"string field stays string", "name='18'", []any{"18"}},
+		{"undeclared field stays string", "zip='01001'", []any{"01001"}},
+		{"non-numeric value stays string", "age='eighteen'", []any{"eighteen"}},
+		{"IN elements are coerced", "age IN ('18','21')", []any{18, 21}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			_, args, err := qb.Validate(WithFieldTypes(types)).ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestValidator_InCondition(t *testing.T) {
+	t.Parallel()
+
+	qb := NewQueryBuilder("users")
+	qb.AddInCondition("ssn", []any{"123"})
+
+	_, _, err := qb.Validate(WithAllowedFields([]string{"id"})).ToSQL()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field 'ssn' is not allowed")
+}
+
+func TestValidator_SortDuplicates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error mode rejects repeated fields", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"name", "-name"})
+
+		_, _, err := qb.Validate(WithSortDuplicates(SortDuplicatesError)).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "duplicate sort field 'name'", err.Error())
+	})
+
+	t.Run("keep-first mode drops later occurrences", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"name", "-age", "-name"})
+
+		sql, _, err := qb.Validate(WithSortDuplicates(SortDuplicatesKeepFirst)).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY name ASC, age DESC", sql)
+	})
+
+	t.Run("duplicates are kept by default", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetSort([]string{"name", "-name"})
+
+		sql, _, err := qb.Validate().ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY name ASC, name DESC", sql)
+	})
+}
+
+func TestValidator_ToCountDistinctSQL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allowed field is counted", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("products")
+
+		sql, _, err := qb.Validate(WithAllowedFields([]string{"category"})).ToCountDistinctSQL("category")
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(DISTINCT category) FROM products", sql)
+	})
+
+	t.Run("field outside the allowlist is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("products")
+
+		_, _, err := qb.Validate(WithAllowedFields([]string{"category"})).ToCountDistinctSQL("cost")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'cost' is not allowed")
+	})
+
+	t.Run("column mapper translates the field", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("products")
+		mapper := func(field string) (string, bool) {
+			return "product_" + field, field == "category"
+		}
+
+		sql, _, err := qb.Validate(WithColumnMapper(mapper)).ToCountDistinctSQL("category")
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(DISTINCT product_category) FROM products", sql)
+	})
+}
+
+func TestValidator_WithHasMany(t *testing.T) {
+	t.Parallel()
+
+	filter, err := parser.ParseFilter("orders.status='paid'")
+	require.NoError(t, err)
+
+	qb := NewQueryBuilder("users")
+	qb.SetFilter(filter)
+
+	sql, args, err := qb.Validate(
+		WithHasMany("orders", "orders.user_id = users.id"),
+		WithAllowedFields([]string{"id", "orders.status"}),
+	).ToSQL()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.status = ?)", sql)
+	assert.Equal(t, []any{"paid"}, args)
+}
+
+func TestValidator_WithStripDisallowedFields(t *testing.T) {
+	t.Parallel()
+
+	allowed := WithAllowedFields([]string{"name", "age", "status"})
+
+	t.Run("disallowed condition is stripped and the rest builds", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && password='x' && status='active'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.Validate(allowed, WithStripDisallowedFields()).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND status = ?)", sql)
+		assert.Equal(t, []any{18, "active"}, args)
+	})
+
+	t.Run("empty groups are pruned", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("(password='x' && token='y') || (name='bob' && (secret=1 || role='admin'))")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.Validate(allowed, WithStripDisallowedFields()).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE name = ?", sql)
+		assert.Equal(t, []any{"bob"}, args)
+	})
+
+	t.Run("caller's filter is left unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		const input = "(password='x' && token='y') || (name='bob' && (secret=1 || role='admin'))"
+		filter, err := parser.ParseFilter(input)
+		require.NoError(t, err)
+		want, err := parser.ParseFilter(input)
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(allowed, WithStripDisallowedFields()).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, want, filter)
+	})
+
+	t.Run("entirely disallowed filter yields no WHERE", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("password='x' || token='y'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.Validate(allowed, WithStripDisallowedFields()).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("fields and sorts are stripped", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"name", "password", "age"})
+		qb.SetSort([]string{"-password", "name"})
+
+		sql, _, err := qb.Validate(allowed, WithStripDisallowedFields()).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT name, age FROM users ORDER BY name ASC", sql)
+	})
+
+	t.Run("require filter still applies after stripping", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("password='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(allowed, WithStripDisallowedFields(), WithRequireFilter()).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "at least one filter condition is required", err.Error())
+	})
+
+	t.Run("disallowed fields error by default", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && password='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(allowed).ToSQL()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+}
+
+func TestValidator_WithMaxArgs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects queries over the limit", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("age>18 && id IN (1,2,3)")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(WithMaxArgs(3)).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "4 query arguments exceed maximum allowed of 3", err.Error())
+	})
+
+	t.Run("allows queries at the limit", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("id IN (1,2,3)")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, args, err := qb.Validate(WithMaxArgs(3)).ToSQL()
+
+		require.NoError(t, err)
+		assert.Len(t, args, 3)
+	})
+}
+
+func TestValidator_WithJSONColumns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allowed JSON column allows its paths", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("data.country='US'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetDialect(DialectPostgres)
+		qb.SetFilter(filter)
+
+		sql, args, err := qb.Validate(
+			WithJSONColumns("data"),
+			WithAllowedFields([]string{"id", "data"}),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE data->>'country' = ?", sql)
+		assert.Equal(t, []any{"US"}, args)
+	})
+
+	t.Run("forbidden JSON column forbids its paths", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("secrets.token='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		_, _, err = qb.Validate(
+			WithJSONColumns("secrets"),
+			WithForbiddenFields([]string{"secrets"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "field 'secrets.token' is forbidden", err.Error())
+	})
+}
+
+func TestValidator_WithSnakeCaseFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("filter and sort are converted", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("createdAt>=2024-01-01 && userID=7")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("orders")
+		qb.SetFilter(filter)
+		qb.SetFields([]string{"id", "userID", "createdAt"})
+		qb.SetSort([]string{"-createdAt", "HTTPStatus"})
+
+		sql, _, err := qb.Validate(
+			WithSnakeCaseFields(),
+			WithAllowedFields([]string{"id", "user_id", "created_at", "http_status"}),
+		).ToSQL()
+
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, user_id, created_at FROM orders WHERE (created_at >= ? AND user_id = ?) ORDER BY created_at DESC, http_status ASC", sql)
+	})
+
+	t.Run("errors name the field as sent", func(t *testing.T) {
+		t.Parallel()
+
+		qb := NewQueryBuilder("users")
+		qb.SetFields([]string{"passwordHash"})
+
+		_, _, err := qb.Validate(
+			WithSnakeCaseFields(),
+			WithForbiddenFields([]string{"password_hash"}),
+		).ToSQL()
+
+		require.Error(t, err)
+		assert.Equal(t, "field 'passwordHash' is forbidden", err.Error())
+	})
+}
+
+func TestToSnakeCase(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"createdAt":     "created_at",
+		"userID":        "user_id",
+		"HTTPStatus":    "http_status",
+		"id":            "id",
+		"ID":            "id",
+		"address2Line":  "address2_line",
+		"already_snake": "already_snake",
+		"users.ownerID": "users.owner_id",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, toSnakeCase(input), input)
+	}
+}
+
+func TestValidator_WithFieldOperators(t *testing.T) {
+	t.Parallel()
+
+	operators := WithFieldOperators(map[string][]string{
+		"name": {"=", "like"},
+		"age":  {"=", ">", "<", "<>"},
+	})
+
+	tests := []struct {
+		name   string
+		filter string
+		errMsg string
+	}{
+		{name: "allowed operators", filter: "name LIKE 'a%' && age > 18 && age <> 30"},
+		{name: "fields without rules allow all", filter: "status IN ('a','b')"},
+		{name: "disallowed operator", filter: "name ILIKE 'a%'", errMsg: "operator 'ILIKE' is not allowed for field 'name'"},
+		{name: "disallowed inside a group", filter: "status='a' && (age >= 18 || name='bob')", errMsg: "operator '>=' is not allowed for field 'age'"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			_, _, err = qb.Validate(operators).ToSQL()
+
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.errMsg, err.Error())
+		})
+	}
+
+	t.Run("combined with the allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("name IN ('a','b') && password='x'")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		errs := qb.Validate(operators, WithAllowedFields([]string{"name", "age"})).Validate()
+
+		require.Len(t, errs, 2)
+		assert.Equal(t, "operator 'IN' is not allowed for field 'name'", errs[0].Error())
+		assert.Contains(t, errs[1].Error(), "field 'password' is not allowed")
+	})
+
+	t.Run("rules match the canonical field", func(t *testing.T) {
+		t.Parallel()
+
+		onlyEquals := WithFieldOperators(map[string][]string{"name": {"="}})
+
+		tests := []struct {
+			name   string
+			filter string
+			opts   []ValidateOption
+			errMsg string
+		}{
+			{
+				name:   "case-insensitive field",
+				filter: "NAME LIKE 'x%'",
+				opts:   []ValidateOption{onlyEquals, WithCaseInsensitiveFields()},
+				errMsg: "operator 'LIKE' is not allowed for field 'NAME'",
+			},
+			{
+				name:   "table-qualified field",
+				filter: "users.name LIKE 'x%'",
+				opts:   []ValidateOption{onlyEquals},
+				errMsg: "operator 'LIKE' is not allowed for field 'users.name'",
+			},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				filter, err := parser.ParseFilter(tc.filter)
+				require.NoError(t, err)
+
+				qb := NewQueryBuilder("users")
+				qb.SetFilter(filter)
+
+				_, _, err = qb.Validate(tc.opts...).ToSQL()
+
+				require.Error(t, err)
+				assert.Equal(t, tc.errMsg, err.Error())
+			})
+		}
+	})
+}
+
+func TestValidator_WithOperators(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter string
+		opts   []ValidateOption
+		errMsg string
+	}{
+		{
+			name:   "forbidden LIKE",
+			filter: "age>18 && name LIKE 'J%'",
+			opts:   []ValidateOption{WithForbiddenOperators("like", "ILIKE")},
+			errMsg: "operator 'LIKE' is not allowed",
+		},
+		{
+			name:   "forbidden <> matches !=",
+			filter: "status<>'banned'",
+			opts:   []ValidateOption{WithForbiddenOperators("<>")},
+			errMsg: "operator '!=' is not allowed",
+		},
+		{
+			name:   "other operators pass the blocklist",
+			filter: "age>18 && name='J'",
+			opts:   []ValidateOption{WithForbiddenOperators("LIKE")},
+		},
+		{
+			name:   "only = and IN allowed",
+			filter: "status IN ('a','b') && age>18",
+			opts:   []ValidateOption{WithAllowedOperators("=", "IN")},
+			errMsg: "operator '>' is not allowed",
+		},
+		{
+			name:   "allowed operators pass",
+			filter: "status IN ('a','b') && id=1",
+			opts:   []ValidateOption{WithAllowedOperators("=", "IN")},
+		},
+		{
+			name:   "forbidden wins over allowed",
+			filter: "id=1",
+			opts:   []ValidateOption{WithAllowedOperators("="), WithForbiddenOperators("=")},
+			errMsg: "operator '=' is not allowed",
+		},
+		{
+			name:   "nested groups are checked",
+			filter: "id=1 && (name='a' || name ILIKE 'b%')",
+			opts:   []ValidateOption{WithForbiddenOperators("ILIKE")},
+			errMsg: "operator 'ILIKE' is not allowed",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			_, _, err = qb.Validate(tc.opts...).ToSQL()
+
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.errMsg, err.Error())
+		})
+	}
+}
+
+func TestValidator_WithAllowedValues(t *testing.T) {
+	t.Parallel()
+
+	status := WithAllowedValues("status", "active", "pending", "archived")
+
+	tests := []struct {
+		name   string
+		filter string
+		errMsg string
+	}{
+		{name: "valid value", filter: "status='active' && age>18"},
+		{name: "invalid scalar", filter: "status='bogus'", errMsg: "value 'bogus' is not allowed for field 'status'"},
+		{name: "valid IN list", filter: "status IN ('active','pending')"},
+		{name: "IN list with an invalid element", filter: "status NOT IN ('active','archivd')", errMsg: "value 'archivd' is not allowed for field 'status'"},
+		{name: "null elements pass", filter: "status IN ('active', null) || status IS NULL"},
+		{name: "pattern operators are not checked", filter: "status LIKE 'act%'"},
+		{name: "nested groups are checked", filter: "age>18 && (status='active' || status='deleted')", errMsg: "value 'deleted' is not allowed for field 'status'"},
+		{name: "numbers match on text", filter: "status=1", errMsg: "value '1' is not allowed for field 'status'"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := parser.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			qb := NewQueryBuilder("users")
+			qb.SetFilter(filter)
+
+			_, _, err = qb.Validate(status).ToSQL()
+
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tc.errMsg, err.Error())
+		})
+	}
+
+	t.Run("reports every invalid element", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parser.ParseFilter("status IN ('a','active','b')")
+		require.NoError(t, err)
+
+		qb := NewQueryBuilder("users")
+		qb.SetFilter(filter)
+
+		errs := qb.Validate(status).Validate()
+		require.Len(t, errs, 2)
+		assert.Equal(t, "value 'a' is not allowed for field 'status'", errs[0].Error())
+		assert.Equal(t, "value 'b' is not allowed for field 'status'", errs[1].Error())
+	})
+
+	t.Run("values match the canonical field", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name   string
+			filter string
+			opts   []ValidateOption
+			errMsg string
+		}{
+			{
+				name:   "case-insensitive field",
+				filter: "STATUS='bogus'",
+				opts:   []ValidateOption{status, WithCaseInsensitiveFields()},
+				errMsg: "value 'bogus' is not allowed for field 'STATUS'",
+			},
+			{
+				name:   "table-qualified field",
+				filter: "users.status='bogus'",
+				opts:   []ValidateOption{status},
+				errMsg: "value 'bogus' is not allowed for field 'users.status'",
+			},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				filter, err := parser.ParseFilter(tc.filter)
+				require.NoError(t, err)
+
+				qb := NewQueryBuilder("users")
+				qb.SetFilter(filter)
+
+				_, _, err = qb.Validate(tc.opts...).ToSQL()
+
+				require.Error(t, err)
+				assert.Equal(t, tc.errMsg, err.Error())
+			})
+		}
+	})
+}
+
+func TestValidator_FieldAndSubExpr(t *testing.T) {
+	t.Parallel()
+
+	// The grammar never produces a comparison with both a field and a
+	// subexpression, but ASTs built by hand can. Both must be validated.
+	sub, err := parser.ParseFilter("secret=1 && name='bob'")
+	require.NoError(t, err)
+
+	comp := &parser.Comparison{
+		Left: &parser.Primary{Field: "password", SubExpr: sub.Expression},
+	}
+	qb := NewQueryBuilder("users")
+	qb.SetFilter(&parser.Filter{
+		Expression: &parser.OrExpr{And: []*parser.AndExpr{{Comparison: []*parser.Comparison{comp}}}},
+	})
+
+	errs := qb.Validate(WithAllowedFields([]string{"name"})).Validate()
+
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "field 'password' is not allowed")
+	assert.Contains(t, errs[1].Error(), "field 'secret' is not allowed")
+}
+
+func TestValidateFilterString(t *testing.T) {
+	t.Parallel()
+
+	opts := []ValidateOption{WithAllowedFields([]string{"name", "age"})}
+
+	t.Run("valid filter", func(t *testing.T) {
+		t.Parallel()
+
+		err := ValidateFilterString("age>18 && name='bob'", opts...)
+		assert.NoError(t, err)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+
+		err := ValidateFilterString("age>>18", opts...)
+		assert.Error(t, err)
+	})
+
+	t.Run("disallowed field", func(t *testing.T) {
+		t.Parallel()
+
+		err := ValidateFilterString("age>18 && password='x'", opts...)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+}
@@ -0,0 +1,83 @@
+// Command gin-sqlx shows how to serve a filtered, paginated list endpoint
+// with Gin, sqlx and RestQL.
+//
+//	go run . -dsn "postgres://localhost/app?sslmode=disable"
+//	curl "localhost:8080/users?filter=age>18&fields=id,name&sort=-id&limit=10"
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"github.com/lucasvillarinho/restql"
+	"github.com/lucasvillarinho/restql/ginrql"
+)
+
+// User is a row of the users table.
+type User struct {
+	ID     int    `db:"id" json:"id"`
+	Name   string `db:"name" json:"name"`
+	Email  string `db:"email" json:"email"`
+	Age    int    `db:"age" json:"age"`
+	Status string `db:"status" json:"status"`
+}
+
+func main() {
+	dsn := flag.String("dsn", "postgres://localhost/app?sslmode=disable", "PostgreSQL connection string")
+	addr := flag.String("addr", ":8080", "HTTP listen address")
+	flag.Parse()
+
+	db, err := sqlx.Open("postgres", *dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	rql := restql.NewRestQL(restql.WithPlaceholder("$1"))
+
+	r := gin.Default()
+	r.GET("/users", func(c *gin.Context) {
+		query, err := ginrql.FromGin(c, rql, "users",
+			restql.WithAllowedFields([]string{"id", "name", "email", "age", "status"}),
+			restql.WithMaxLimit(100),
+			restql.WithMaxOffset(10000),
+		)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sql, args, err := query.ToSQL()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Select into maps so partial field selections scan cleanly.
+		rows, err := db.Queryx(sql, args...)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "database error"})
+			return
+		}
+		defer rows.Close()
+
+		users := make([]map[string]any, 0)
+		for rows.Next() {
+			row := make(map[string]any)
+			if err := rows.MapScan(row); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "database error"})
+				return
+			}
+			users = append(users, row)
+		}
+
+		c.JSON(http.StatusOK, users)
+	})
+
+	log.Fatal(r.Run(*addr))
+}
@@ -0,0 +1,33 @@
+// Package ginrql provides Gin framework bindings for RestQL.
+//
+// It lives in its own package so that applications not using Gin do not
+// import it.
+package ginrql
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/lucasvillarinho/restql"
+)
+
+// FromGin parses query parameters from a Gin context and returns a SQLBuilder
+// with optional validation.
+// It reads c.Request the same way as RestQL.FromRequest, so JSON POST bodies
+// are supported as well.
+//
+// Example:
+//
+//	r.GET("/users", func(c *gin.Context) {
+//	    query, err := ginrql.FromGin(c, rql, "users",
+//	        restql.WithAllowedFields([]string{"id", "name", "email"}),
+//	        restql.WithMaxLimit(100),
+//	    )
+//	    if err != nil {
+//	        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//	    sql, args, err := query.ToSQL()
+//	})
+func FromGin(c *gin.Context, rql *restql.RestQL, table string, opts ...restql.ValidateOption) (restql.SQLBuilder, error) {
+	return rql.FromRequest(c.Request, table, opts...)
+}
@@ -0,0 +1,55 @@
+package ginrql_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lucasvillarinho/restql"
+	"github.com/lucasvillarinho/restql/ginrql"
+)
+
+func TestFromGin(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+
+	t.Run("builds SQL from request query", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL(restql.WithPlaceholder("$1"))
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/users?filter=age>18&fields=id,name&sort=-id&limit=10", nil)
+
+		query, err := ginrql.FromGin(c, rql, "users",
+			restql.WithAllowedFields([]string{"id", "name", "age"}),
+			restql.WithMaxLimit(100),
+		)
+		require.NoError(t, err)
+
+		sql, args, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name FROM users WHERE age > $1 ORDER BY id DESC LIMIT 10", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("rejects disallowed fields", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/users?fields=password", nil)
+
+		query, err := ginrql.FromGin(c, rql, "users",
+			restql.WithAllowedFields([]string{"id", "name"}),
+		)
+		require.NoError(t, err)
+
+		_, _, err = query.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+}
@@ -1,8 +1,25 @@
 package parser
 
+import (
+	"fmt"
+	"time"
+)
+
 // Filter represents the root of the filter expression tree.
 type Filter struct {
 	Expression *OrExpr `parser:"@@"`
+
+	err error // Error from building the filter in code, see Err
+}
+
+// Err returns the error recorded while building the filter with the DSL
+// (e.g. an unsupported value type), or nil. Query builders report it
+// instead of emitting SQL.
+func (f *Filter) Err() error {
+	if f == nil {
+		return nil
+	}
+	return f.err
 }
 
 // OrExpr represents an OR expression (lowest precedence).
@@ -16,32 +33,101 @@ type AndExpr struct {
 }
 
 // Comparison represents a comparison operation.
+// A comparison with only a Left field is a bare boolean predicate. Not
+// negates the comparison: a bare predicate (e.g. "!active"), a group, or any
+// other comparison (e.g. "!age>18"). To is the upper bound of a range literal
+// (e.g. "age=18..65"), with Right as the lower bound.
 type Comparison struct {
+	Not   bool       `parser:"@\"!\"?"`
 	Left  *Primary   `parser:"@@"`
 	Op    *Operator  `parser:"@@?"`
-	Right *Value     `parser:"@@?"`
-	Null  *NullCheck `parser:"@@?"`
+	Null  *NullCheck `parser:"( @@"`
+	Right *Value     `parser:"| @@"`
+	To    *Value     `parser:"  ( \"..\" @@ )? )?"`
+}
+
+// Bare reports whether the comparison is a bare boolean predicate: a field
+// with no operator, such as "active" or "!active".
+func (c *Comparison) Bare() bool {
+	return c.Op == nil && c.Null == nil && c.Right == nil && c.Left != nil && c.Left.SubExpr == nil
 }
 
-// Primary represents a field or a parenthesized expression.
+// Primary represents a field (optionally followed by arithmetic, e.g.
+// "price * quantity") or a parenthesized expression.
 type Primary struct {
-	Field   string  `parser:"@Ident |"`
-	SubExpr *OrExpr `parser:"\"(\" @@ \")\""`
+	Field   string        `parser:"( @Ident"`
+	Arith   []*Arithmetic `parser:"  @@* ) |"`
+	SubExpr *OrExpr       `parser:"\"(\" @@ \")\""`
+}
+
+// Fields returns all field names referenced by the primary, including
+// arithmetic operands.
+func (p *Primary) Fields() []string {
+	if p.Field == "" {
+		return nil
+	}
+	fields := []string{p.Field}
+	for _, a := range p.Arith {
+		if a != nil && a.Field != "" {
+			fields = append(fields, a.Field)
+		}
+	}
+	return fields
+}
+
+// Arithmetic represents an arithmetic operation on the left side of a
+// comparison, with a field or numeric literal operand.
+type Arithmetic struct {
+	Op     string `parser:"@(\"+\" | \"-\" | \"*\" | \"/\" | \"%\")"`
+	Field  string `parser:"( @Ident"`
+	Number string `parser:"| @(Float | Int) )"`
+}
+
+// Operand returns the arithmetic operand as written.
+func (a *Arithmetic) Operand() string {
+	if a.Field != "" {
+		return a.Field
+	}
+	return a.Number
 }
 
 // Operator represents comparison operators.
 type Operator struct {
-	Equal          bool `parser:"@\"=\""`
-	NotEqual       bool `parser:"| @(\"!=\" | \"<>\")"`
-	GreaterOrEqual bool `parser:"| @\">=\""`
-	LessOrEqual    bool `parser:"| @\"<=\""`
-	Greater        bool `parser:"| @\">\""`
-	Less           bool `parser:"| @\"<\""`
-	Like           bool `parser:"| @(\"LIKE\" | \"like\")"`
-	NotLike        bool `parser:"| @(\"NOT\" \"LIKE\" | \"not\" \"like\")"`
-	In             bool `parser:"| @(\"IN\" | \"in\")"`
-	NotIn          bool `parser:"| @(\"NOT\" \"IN\" | \"not\" \"in\")"`
-	Is             bool `parser:"| @(\"IS\" | \"is\")"`
+	Equal           bool `parser:"@\"=\""`
+	NullSafeEqual   bool `parser:"| @\"<=>\""`
+	NotEqual        bool `parser:"| @(\"!=\" | \"<>\")"`
+	GreaterOrEqual  bool `parser:"| @\">=\""`
+	LessOrEqual     bool `parser:"| @\"<=\""`
+	Greater         bool `parser:"| @\">\""`
+	Less            bool `parser:"| @\"<\""`
+	Like            bool `parser:"| @(\"LIKE\" | \"like\")"`
+	NotLike         bool `parser:"| @(\"NOT\" \"LIKE\" | \"not\" \"like\")"`
+	ILike           bool `parser:"| @(\"ILIKE\" | \"ilike\")"`
+	NotILike        bool `parser:"| @(\"NOT\" \"ILIKE\" | \"not\" \"ilike\")"`
+	In              bool `parser:"| @(\"IN\" | \"in\")"`
+	NotIn           bool `parser:"| @(\"NOT\" \"IN\" | \"not\" \"in\")"`
+	DistinctFrom    bool `parser:"| @(\"IS\" \"DISTINCT\" \"FROM\" | \"is\" \"distinct\" \"from\")"`
+	NotDistinctFrom bool `parser:"| @(\"IS\" \"NOT\" \"DISTINCT\" \"FROM\" | \"is\" \"not\" \"distinct\" \"from\")"`
+	Is              bool `parser:"| @(\"IS\" | \"is\")"`
+	Contains        bool `parser:"| @(\"CONTAINS\" | \"contains\")"`
+	Regexp          bool `parser:"| @(\"REGEXP\" | \"regexp\")"`
+	NotRegexp       bool `parser:"| @(\"NOT\" \"REGEXP\" | \"not\" \"regexp\")"`
+	Tilde           bool `parser:"| @\"~\""`
+	NotTilde        bool `parser:"| @\"!~\""`
+}
+
+// ResolveTilde returns the operator that "~" and "!~" stand for: LIKE and
+// NOT LIKE, or ILIKE and NOT ILIKE when ilike is set. Other operators are
+// returned unchanged.
+func (o *Operator) ResolveTilde(ilike bool) *Operator {
+	switch {
+	case o == nil || !o.Tilde && !o.NotTilde:
+		return o
+	case ilike:
+		return &Operator{ILike: o.Tilde, NotILike: o.NotTilde}
+	default:
+		return &Operator{Like: o.Tilde, NotLike: o.NotTilde}
+	}
 }
 
 // String returns the operator as a string.
@@ -49,6 +135,8 @@ func (o *Operator) String() string {
 	switch {
 	case o.Equal:
 		return "="
+	case o.NullSafeEqual:
+		return "<=>"
 	case o.NotEqual:
 		return "!="
 	case o.GreaterOrEqual:
@@ -63,32 +151,93 @@ func (o *Operator) String() string {
 		return "LIKE"
 	case o.NotLike:
 		return "NOT LIKE"
+	case o.ILike:
+		return "ILIKE"
+	case o.NotILike:
+		return "NOT ILIKE"
 	case o.In:
 		return "IN"
 	case o.NotIn:
 		return "NOT IN"
+	case o.DistinctFrom:
+		return "IS DISTINCT FROM"
+	case o.NotDistinctFrom:
+		return "IS NOT DISTINCT FROM"
 	case o.Is:
 		return "IS"
+	case o.Contains:
+		return "@>"
+	case o.Regexp:
+		return "REGEXP"
+	case o.NotRegexp:
+		return "NOT REGEXP"
+	case o.Tilde:
+		return "~"
+	case o.NotTilde:
+		return "!~"
 	default:
 		return ""
 	}
 }
 
 // NullCheck represents NULL checks (IS NULL, IS NOT NULL).
+// The null literal is also accepted after = and != (e.g. "status != null").
 type NullCheck struct {
 	IsNull    bool `parser:"@(\"NULL\" | \"null\")"`
 	IsNotNull bool `parser:"| @(\"NOT\" \"NULL\" | \"not\" \"null\")"`
 }
 
 // Value represents a value in a comparison.
+// Null is only reachable inside arrays (e.g. "status IN ('a', null)"); a
+// top-level null is parsed as a NullCheck.
 type Value struct {
-	String  *string  `parser:"  @String"`
+	Date    *Date    `parser:"  @DateTime"`
+	String  *string  `parser:"| @String"`
 	Number  *float64 `parser:"| @Float"`
 	Int     *int     `parser:"| @Int"`
 	Boolean *Boolean `parser:"| @@"`
+	Null    bool     `parser:"| @(\"NULL\" | \"null\")"`
 	Array   *Array   `parser:"| @@"`
 }
 
+// dateLayouts are the accepted ISO 8601 date and datetime layouts.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	time.DateOnly,
+}
+
+// Date represents an ISO 8601 date (2024-01-01) or datetime
+// (2024-01-01T10:00:00Z) literal.
+type Date struct {
+	Time     time.Time
+	DateOnly bool
+}
+
+// Capture parses the date literal, implementing participle.Capture.
+func (d *Date) Capture(values []string) error {
+	raw := values[0]
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			d.Time = t
+			d.DateOnly = layout == time.DateOnly
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q", raw)
+}
+
+// String returns the date in normalized form: YYYY-MM-DD for dates and
+// RFC 3339 for datetimes.
+func (d *Date) String() string {
+	if d.DateOnly {
+		return d.Time.Format(time.DateOnly)
+	}
+	return d.Time.Format(time.RFC3339Nano)
+}
+
 // Boolean represents a boolean value.
 type Boolean struct {
 	True  bool `parser:"  @(\"true\" | \"TRUE\")"`
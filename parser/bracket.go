@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bracketOperators maps bracket operator names to filter operators.
+var bracketOperators = map[string]string{
+	"eq":     "=",
+	"ne":     "!=",
+	"gt":     ">",
+	"gte":    ">=",
+	"lt":     "<",
+	"lte":    "<=",
+	"like":   "LIKE",
+	"nlike":  "NOT LIKE",
+	"ilike":  "ILIKE",
+	"nilike": "NOT ILIKE",
+	"in":     "IN",
+	"nin":    "NOT IN",
+	"is":     "IS",
+}
+
+// rewriteBracketOperators rewrites bracket operators into the symbolic
+// syntax, e.g. "age[gte]:18" into "age >= 18". Quoted strings are copied
+// verbatim, and everything outside brackets is left as written.
+func rewriteBracketOperators(filter string) (string, error) {
+	var out strings.Builder
+	out.Grow(len(filter))
+
+	for i := 0; i < len(filter); i++ {
+		c := filter[i]
+		switch c {
+		case '\'', '"':
+			end := strings.IndexByte(filter[i+1:], c)
+			if end < 0 {
+				out.WriteString(filter[i:])
+				return out.String(), nil
+			}
+			out.WriteString(filter[i : i+end+2])
+			i += end + 1
+		case '[':
+			end := strings.IndexByte(filter[i:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("invalid filter syntax: unclosed '[' (filter: %s)", filter)
+			}
+			name := filter[i+1 : i+end]
+			op, ok := bracketOperators[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return "", fmt.Errorf("invalid filter syntax: unknown operator '%s' (filter: %s)", name, filter)
+			}
+			i += end + 1
+			if i >= len(filter) || filter[i] != ':' {
+				return "", fmt.Errorf("invalid filter syntax: expected ':' after '[%s]' (filter: %s)", name, filter)
+			}
+			out.WriteString(" " + op + " ")
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), nil
+}
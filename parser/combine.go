@@ -0,0 +1,68 @@
+package parser
+
+import "errors"
+
+// AndFilters combines two filters with AND.
+// Operands containing a top-level OR are wrapped in a subexpression so that
+// precedence is preserved. A nil operand returns the other filter unchanged.
+// Errors recorded by the DSL in either operand are kept (see Filter.Err).
+func AndFilters(a, b *Filter) *Filter {
+	if err := errors.Join(a.Err(), b.Err()); err != nil {
+		return &Filter{err: err}
+	}
+	if isEmptyFilter(a) {
+		return b
+	}
+	if isEmptyFilter(b) {
+		return a
+	}
+
+	comparisons := make([]*Comparison, 0)
+	comparisons = append(comparisons, andOperands(a.Expression)...)
+	comparisons = append(comparisons, andOperands(b.Expression)...)
+
+	return &Filter{
+		Expression: &OrExpr{
+			And: []*AndExpr{{Comparison: comparisons}},
+		},
+	}
+}
+
+// OrFilters combines two filters with OR.
+// Since OR has the lowest precedence, the AND groups of both operands are
+// spliced directly. A nil operand returns the other filter unchanged.
+// Errors recorded by the DSL in either operand are kept (see Filter.Err).
+func OrFilters(a, b *Filter) *Filter {
+	if err := errors.Join(a.Err(), b.Err()); err != nil {
+		return &Filter{err: err}
+	}
+	if isEmptyFilter(a) {
+		return b
+	}
+	if isEmptyFilter(b) {
+		return a
+	}
+
+	and := make([]*AndExpr, 0, len(a.Expression.And)+len(b.Expression.And))
+	and = append(and, a.Expression.And...)
+	and = append(and, b.Expression.And...)
+
+	return &Filter{
+		Expression: &OrExpr{And: and},
+	}
+}
+
+// isEmptyFilter reports whether the filter has no expression.
+func isEmptyFilter(f *Filter) bool {
+	return f == nil || f.Expression == nil || len(f.Expression.And) == 0
+}
+
+// andOperands returns the comparisons to splice into an AND expression.
+// A single AND group is spliced as-is; an OR expression is wrapped in a
+// parenthesized subexpression.
+func andOperands(expr *OrExpr) []*Comparison {
+	if len(expr.And) == 1 {
+		return expr.And[0].Comparison
+	}
+	return []*Comparison{{Left: &Primary{SubExpr: expr}}}
+}
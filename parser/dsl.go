@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// FieldRef starts a comparison in the filter DSL. Create one with F.
+type FieldRef struct {
+	field string
+}
+
+// F references a field for building a filter programmatically, without
+// concatenating filter strings. Values are always bound as arguments.
+// The field name is not escaped and should not come from user input.
+//
+// Example:
+//
+//	filter := parser.F("age").Gt(18).And(parser.F("status").Eq("active"))
+func F(field string) FieldRef {
+	return FieldRef{field: field}
+}
+
+// Eq builds "field = value".
+func (f FieldRef) Eq(value any) *Filter {
+	return f.compare(&Operator{Equal: true}, value)
+}
+
+// Ne builds "field != value".
+func (f FieldRef) Ne(value any) *Filter {
+	return f.compare(&Operator{NotEqual: true}, value)
+}
+
+// Gt builds "field > value".
+func (f FieldRef) Gt(value any) *Filter {
+	return f.compare(&Operator{Greater: true}, value)
+}
+
+// Gte builds "field >= value".
+func (f FieldRef) Gte(value any) *Filter {
+	return f.compare(&Operator{GreaterOrEqual: true}, value)
+}
+
+// Lt builds "field < value".
+func (f FieldRef) Lt(value any) *Filter {
+	return f.compare(&Operator{Less: true}, value)
+}
+
+// Lte builds "field <= value".
+func (f FieldRef) Lte(value any) *Filter {
+	return f.compare(&Operator{LessOrEqual: true}, value)
+}
+
+// Like builds "field LIKE pattern".
+func (f FieldRef) Like(pattern string) *Filter {
+	return f.compare(&Operator{Like: true}, pattern)
+}
+
+// NotLike builds "field NOT LIKE pattern".
+func (f FieldRef) NotLike(pattern string) *Filter {
+	return f.compare(&Operator{NotLike: true}, pattern)
+}
+
+// ILike builds "field ILIKE pattern".
+func (f FieldRef) ILike(pattern string) *Filter {
+	return f.compare(&Operator{ILike: true}, pattern)
+}
+
+// In builds "field IN (values...)". A single slice argument is expanded,
+// so F("id").In(ids) and F("id").In(1, 2, 3) are equivalent.
+func (f FieldRef) In(values ...any) *Filter {
+	return f.compare(&Operator{In: true}, spread(values))
+}
+
+// NotIn builds "field NOT IN (values...)". A single slice argument is
+// expanded as in In.
+func (f FieldRef) NotIn(values ...any) *Filter {
+	return f.compare(&Operator{NotIn: true}, spread(values))
+}
+
+// IsNull builds "field IS NULL".
+func (f FieldRef) IsNull() *Filter {
+	return f.filter(&Comparison{Left: &Primary{Field: f.field}, Op: &Operator{Is: true}, Null: &NullCheck{IsNull: true}})
+}
+
+// IsNotNull builds "field IS NOT NULL".
+func (f FieldRef) IsNotNull() *Filter {
+	return f.filter(&Comparison{Left: &Primary{Field: f.field}, Op: &Operator{Is: true}, Null: &NullCheck{IsNotNull: true}})
+}
+
+// compare builds a single comparison of the field against a Go value. A nil
+// value compares with IS NULL or IS NOT NULL, as "= null" does in filters.
+func (f FieldRef) compare(op *Operator, value any) *Filter {
+	right, err := valueOf(value)
+	if err != nil {
+		return &Filter{err: fmt.Errorf("invalid value for field '%s': %w", f.field, err)}
+	}
+	if right.Null {
+		switch {
+		case op.Equal:
+			return f.IsNull()
+		case op.NotEqual:
+			return f.IsNotNull()
+		}
+		return &Filter{err: fmt.Errorf("operator %s on field '%s' cannot compare with null", op.String(), f.field)}
+	}
+	return f.filter(&Comparison{Left: &Primary{Field: f.field}, Op: op, Right: right})
+}
+
+// filter wraps a single comparison in a Filter.
+func (f FieldRef) filter(comp *Comparison) *Filter {
+	return &Filter{
+		Expression: &OrExpr{
+			And: []*AndExpr{{Comparison: []*Comparison{comp}}},
+		},
+	}
+}
+
+// And combines the filter with other using AND. See AndFilters.
+func (f *Filter) And(other *Filter) *Filter {
+	return AndFilters(f, other)
+}
+
+// Or combines the filter with other using OR. See OrFilters.
+func (f *Filter) Or(other *Filter) *Filter {
+	return OrFilters(f, other)
+}
+
+// spread returns the elements of a single slice argument, or values as is.
+func spread(values []any) any {
+	if len(values) == 1 {
+		if kind := reflect.ValueOf(values[0]).Kind(); kind == reflect.Slice || kind == reflect.Array {
+			return values[0]
+		}
+	}
+	return values
+}
+
+// valueOf converts a Go value into a filter Value. Strings, integers,
+// floats, bools, time.Time, nil, pointers to those and slices of those are
+// supported; any other type is an error.
+func valueOf(value any) (*Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return &Value{Null: true}, nil
+	case string:
+		s := "'" + v + "'"
+		return &Value{String: &s}, nil
+	case bool:
+		return &Value{Boolean: &Boolean{True: v, False: !v}}, nil
+	case time.Time:
+		return &Value{Date: &Date{Time: v}}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := int(rv.Int())
+		return &Value{Int: &n}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := int(rv.Uint())
+		return &Value{Int: &n}, nil
+	case reflect.Float32, reflect.Float64:
+		n := rv.Float()
+		return &Value{Number: &n}, nil
+	case reflect.String:
+		return valueOf(rv.String())
+	case reflect.Bool:
+		return valueOf(rv.Bool())
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return &Value{Null: true}, nil
+		}
+		return valueOf(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		values := make([]*Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			element, err := valueOf(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			if element.Array != nil {
+				return nil, fmt.Errorf("unsupported nested list %T", value)
+			}
+			values = append(values, element)
+		}
+		return &Value{Array: &Array{Values: values}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", value)
+	}
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"io"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// operatorKeywords are the identifiers that precede a comparison value, so a
+// signed number after them is a literal (e.g. "IS DISTINCT FROM -1").
+var operatorKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "FROM": true,
+	"LIKE": true, "ILIKE": true, "REGEXP": true, "CONTAINS": true,
+}
+
+// arithDefinition wraps the filter lexer so that a signed number directly
+// after an operand is lexed as a binary operator followed by the number:
+// "a-1" lexes like "a - 1". Elsewhere the sign belongs to the number, as in
+// "age>-1" or "id IN (-1, -2)".
+type arithDefinition struct {
+	lexer.Definition
+}
+
+// Lex returns a lexer splitting signed numbers that follow an operand.
+func (d arithDefinition) Lex(filename string, r io.Reader) (lexer.Lexer, error) {
+	l, err := d.Definition.Lex(filename, r)
+	if err != nil {
+		return nil, err
+	}
+	symbols := d.Symbols()
+	return &arithLexer{
+		lexer:      l,
+		whitespace: symbols["whitespace"],
+		ident:      symbols["Ident"],
+		integer:    symbols["Int"],
+		float:      symbols["Float"],
+		operators:  symbols["Operators"],
+		punct:      symbols["Punct"],
+	}, nil
+}
+
+// arithLexer is the lexer returned by arithDefinition.
+type arithLexer struct {
+	lexer   lexer.Lexer
+	pending *lexer.Token // Number split from its sign, returned next
+	prev    lexer.Token  // Last token other than whitespace
+
+	whitespace, ident, integer, float, operators, punct lexer.TokenType
+}
+
+// Next returns the next token.
+func (l *arithLexer) Next() (lexer.Token, error) {
+	if l.pending != nil {
+		token := *l.pending
+		l.pending = nil
+		l.prev = token
+		return token, nil
+	}
+
+	token, err := l.lexer.Next()
+	if err != nil {
+		return token, err
+	}
+
+	if l.signed(token) && l.followsOperand() {
+		number := token
+		number.Value = token.Value[1:]
+		number.Pos.Offset++
+		number.Pos.Column++
+		l.pending = &number
+
+		token.Type = l.operators
+		token.Value = token.Value[:1]
+	}
+
+	if token.Type != l.whitespace {
+		l.prev = token
+	}
+	return token, nil
+}
+
+// signed reports whether token is a number with a leading sign.
+func (l *arithLexer) signed(token lexer.Token) bool {
+	if token.Type != l.integer && token.Type != l.float {
+		return false
+	}
+	return strings.HasPrefix(token.Value, "-") || strings.HasPrefix(token.Value, "+")
+}
+
+// followsOperand reports whether the previous token ends an operand: a
+// field, a number or a closing parenthesis.
+func (l *arithLexer) followsOperand() bool {
+	switch l.prev.Type {
+	case l.ident:
+		return !operatorKeywords[strings.ToUpper(l.prev.Value)]
+	case l.integer, l.float:
+		return true
+	case l.punct:
+		return l.prev.Value == ")"
+	}
+	return false
+}
@@ -1,42 +1,172 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
-var (
-	// filterLexer defines the lexer for filter expressions.
-	filterLexer = lexer.MustSimple([]lexer.SimpleRule{
+// defaultIdentPattern matches field names, optionally table-qualified.
+const defaultIdentPattern = `[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*`
+
+// defaultParser is the parser instance used by ParseFilter.
+var defaultParser = mustNewParser()
+
+// Parser parses filter strings with a configurable grammar.
+// A Parser is safe for concurrent use.
+type Parser struct {
+	parser           *participle.Parser[Filter]
+	bracketOperators bool
+}
+
+// ParserOption configures a Parser.
+type ParserOption func(*parserConfig)
+
+// parserConfig holds the settings applied by ParserOptions.
+type parserConfig struct {
+	keywordOperators bool
+	bracketOperators bool
+	identPattern     string
+}
+
+// WithKeywordOperators accepts the AND and OR keywords (any case) as aliases
+// for && and ||. Fields named "and" or "or" can no longer be referenced.
+func WithKeywordOperators() ParserOption {
+	return func(c *parserConfig) {
+		c.keywordOperators = true
+	}
+}
+
+// WithBracketOperators accepts named operators in brackets followed by a
+// colon, e.g. "age[gte]:18 && status[in]:('active','pending')", as used by
+// APIs that avoid symbols in query strings. The symbolic syntax keeps working.
+//
+// Operators: eq, ne, gt, gte, lt, lte, like, nlike, ilike, nilike, in, nin
+// and is (e.g. "deleted_at[is]:null").
+func WithBracketOperators() ParserOption {
+	return func(c *parserConfig) {
+		c.bracketOperators = true
+	}
+}
+
+// WithIdentPattern replaces the regular expression used to match field names,
+// e.g. to allow dashes. The pattern must not match operators or literals.
+func WithIdentPattern(pattern string) ParserOption {
+	return func(c *parserConfig) {
+		c.identPattern = pattern
+	}
+}
+
+// NewParser builds a filter parser with the given options.
+// ParseFilter uses a default instance with no options.
+//
+// Example:
+//
+//	p, err := parser.NewParser(parser.WithKeywordOperators())
+//	filter, err := p.ParseFilter("age > 18 AND status = 'active'")
+func NewParser(opts ...ParserOption) (*Parser, error) {
+	cfg := &parserConfig{identPattern: defaultIdentPattern}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	filterLexer, err := lexer.NewSimple([]lexer.SimpleRule{
 		{Name: "whitespace", Pattern: `\s+`},
+		{Name: "DateTime", Pattern: `\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2})?)?`},
 		{Name: "Float", Pattern: `[-+]?\d+\.\d+`},
 		{Name: "Int", Pattern: `[-+]?\d+`},
 		{Name: "String", Pattern: `'[^']*'|"[^"]*"`},
-		{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
-		{Name: "Operators", Pattern: `>=|<=|!=|<>|&&|\|\||=|>|<`},
-		{Name: "Punct", Pattern: `[(),]`},
+		{Name: "Ident", Pattern: cfg.identPattern},
+		{Name: "Operators", Pattern: `<=>|>=|<=|!=|<>|!~|&&|\|\||=|>|<|!|~|[-+*/%]`},
+		{Name: "Punct", Pattern: `\.\.|[(),]`},
 	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid parser configuration: %w", err)
+	}
 
-	// filterParser is the global parser instance.
-	filterParser = participle.MustBuild[Filter](
-		participle.Lexer(filterLexer),
+	options := []participle.Option{
+		participle.Lexer(arithDefinition{filterLexer}),
 		participle.Elide("whitespace"),
 		participle.UseLookahead(2),
-	)
-)
+	}
+	if cfg.keywordOperators {
+		options = append(options, participle.Map(keywordMapper(filterLexer.Symbols()["Operators"]), "Ident"))
+	}
+
+	p, err := participle.Build[Filter](options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parser configuration: %w", err)
+	}
+
+	return &Parser{parser: p, bracketOperators: cfg.bracketOperators}, nil
+}
+
+// mustNewParser builds the default parser, panicking on error.
+func mustNewParser() *Parser {
+	p, err := NewParser()
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// keywordMapper rewrites AND/OR identifiers into && and || operator tokens.
+func keywordMapper(operators lexer.TokenType) participle.Mapper {
+	return func(token lexer.Token) (lexer.Token, error) {
+		switch strings.ToUpper(token.Value) {
+		case "AND":
+			token.Type, token.Value = operators, "&&"
+		case "OR":
+			token.Type, token.Value = operators, "||"
+		}
+		return token, nil
+	}
+}
 
 // ParseFilter parses a filter string into an AST.
-func ParseFilter(filter string) (*Filter, error) {
+// A leading UTF-8 byte order mark and surrounding whitespace are ignored.
+func (p *Parser) ParseFilter(filter string) (*Filter, error) {
+	filter = strings.TrimSpace(strings.TrimPrefix(filter, "\uFEFF"))
 	if filter == "" {
 		return nil, nil
 	}
 
-	ast, err := filterParser.ParseString("", filter)
+	input := filter
+	if p.bracketOperators {
+		var err error
+		if input, err = rewriteBracketOperators(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	ast, err := p.parser.ParseString("", input)
 	if err != nil {
 		return nil, fmt.Errorf("invalid filter syntax: %s (filter: %s)", err.Error(), filter)
 	}
 
 	return ast, nil
 }
+
+// ParseFilter parses a filter string into an AST using the default parser.
+func ParseFilter(filter string) (*Filter, error) {
+	return defaultParser.ParseFilter(filter)
+}
+
+// ParseFilterStrict is like ParseFilter but returns an error for an empty or
+// whitespace-only filter instead of a nil Filter.
+func (p *Parser) ParseFilterStrict(filter string) (*Filter, error) {
+	ast, err := p.ParseFilter(filter)
+	if err == nil && ast == nil {
+		return nil, errors.New("filter is empty")
+	}
+	return ast, err
+}
+
+// ParseFilterStrict parses a non-empty filter string into an AST using the
+// default parser. See Parser.ParseFilterStrict.
+func ParseFilterStrict(filter string) (*Filter, error) {
+	return defaultParser.ParseFilterStrict(filter)
+}
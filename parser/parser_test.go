@@ -2,6 +2,7 @@ package parser
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -261,6 +262,46 @@ func TestParseFilter_Operators(t *testing.T) {
 		assert.Equal(t, "NOT LIKE", comparison.Op.String())
 	})
 
+	t.Run("ILIKE operator uppercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("name ILIKE '%john%'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.ILike)
+		assert.Equal(t, "ILIKE", comparison.Op.String())
+	})
+
+	t.Run("ILIKE operator lowercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("name ilike '%john%'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.ILike)
+		assert.Equal(t, "ILIKE", comparison.Op.String())
+	})
+
+	t.Run("NOT ILIKE operator uppercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("email NOT ILIKE '%test%'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NotILike)
+		assert.Equal(t, "NOT ILIKE", comparison.Op.String())
+	})
+
+	t.Run("NOT ILIKE operator lowercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("email not ilike '%test%'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NotILike)
+		assert.Equal(t, "NOT ILIKE", comparison.Op.String())
+	})
+
 	t.Run("IN operator uppercase", func(t *testing.T) {
 		t.Parallel()
 		result, err := ParseFilter("status IN ('active', 'pending')")
@@ -301,6 +342,111 @@ func TestParseFilter_Operators(t *testing.T) {
 		assert.Equal(t, "NOT IN", comparison.Op.String())
 	})
 
+	t.Run("CONTAINS operator uppercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("tags CONTAINS 'go'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.Contains)
+		assert.Equal(t, "@>", comparison.Op.String())
+	})
+
+	t.Run("CONTAINS operator lowercase with array", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("tags contains ('go', 'sql')")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.Contains)
+		require.NotNil(t, comparison.Right.Array)
+		assert.Len(t, comparison.Right.Array.Values, 2)
+	})
+
+	t.Run("REGEXP operator uppercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("email REGEXP '@example'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.Regexp)
+		assert.Equal(t, "REGEXP", comparison.Op.String())
+	})
+
+	t.Run("~ tilde operator", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("name~'John'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.Tilde)
+		assert.Equal(t, "~", comparison.Op.String())
+		assert.True(t, comparison.Op.ResolveTilde(false).Like)
+		assert.True(t, comparison.Op.ResolveTilde(true).ILike)
+	})
+
+	t.Run("NOT REGEXP operator uppercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("email NOT REGEXP '@test'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NotRegexp)
+		assert.Equal(t, "NOT REGEXP", comparison.Op.String())
+	})
+
+	t.Run("NOT REGEXP operator lowercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("email not regexp '@test'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NotRegexp)
+		assert.Equal(t, "NOT REGEXP", comparison.Op.String())
+	})
+
+	t.Run("!~ tilde operator", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("name!~'John'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NotTilde)
+		assert.Equal(t, "!~", comparison.Op.String())
+		assert.True(t, comparison.Op.ResolveTilde(false).NotLike)
+		assert.True(t, comparison.Op.ResolveTilde(true).NotILike)
+	})
+
+	t.Run("<=> null-safe equal operator", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("manager_id <=> 5")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NullSafeEqual)
+		assert.Equal(t, "<=>", comparison.Op.String())
+	})
+
+	t.Run("IS DISTINCT FROM operator", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("status IS DISTINCT FROM 'active'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.DistinctFrom)
+		assert.Equal(t, "IS DISTINCT FROM", comparison.Op.String())
+	})
+
+	t.Run("IS NOT DISTINCT FROM operator lowercase", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("manager_id is not distinct from 5")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.True(t, comparison.Op.NotDistinctFrom)
+		assert.Equal(t, "IS NOT DISTINCT FROM", comparison.Op.String())
+	})
+
 	t.Run("IS operator for NULL checks", func(t *testing.T) {
 		t.Parallel()
 		result, err := ParseFilter("deleted_at IS NULL")
@@ -472,6 +618,33 @@ func TestParseFilter_NullChecks(t *testing.T) {
 		assert.True(t, comparison.Null.IsNotNull)
 	})
 
+	t.Run("null literal after equal", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("status = null")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+
+		assert.Equal(t, "status", comparison.Left.Field)
+		assert.True(t, comparison.Op.Equal)
+		require.NotNil(t, comparison.Null)
+		assert.True(t, comparison.Null.IsNull)
+		assert.Nil(t, comparison.Right)
+	})
+
+	t.Run("null literal after not equal", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("status != NULL")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+
+		assert.True(t, comparison.Op.NotEqual)
+		require.NotNil(t, comparison.Null)
+		assert.True(t, comparison.Null.IsNull)
+		assert.Nil(t, comparison.Right)
+	})
+
 	t.Run("NULL check in complex expression", func(t *testing.T) {
 		t.Parallel()
 		result, err := ParseFilter("deleted_at IS NULL && status='active'")
@@ -536,6 +709,32 @@ func TestParseFilter_Arrays(t *testing.T) {
 		assert.Equal(t, 8, *comparison.Right.Array.Values[4].Int)
 	})
 
+	t.Run("IN with negative numbers", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("score IN (-1, -2.5, 3)")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+
+		require.NotNil(t, comparison.Right.Array)
+		require.Len(t, comparison.Right.Array.Values, 3)
+
+		assert.Equal(t, -1, *comparison.Right.Array.Values[0].Int)
+		assert.Equal(t, -2.5, *comparison.Right.Array.Values[1].Number)
+		assert.Equal(t, 3, *comparison.Right.Array.Values[2].Int)
+	})
+
+	t.Run("NOT IN with unspaced negative numbers", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("id NOT IN(-1,-2)")
+
+		require.NoError(t, err)
+		values, kind := result.Expression.And[0].Comparison[0].Right.Resolve()
+
+		assert.Equal(t, KindArray, kind)
+		assert.Equal(t, []any{-1, -2}, values)
+	})
+
 	t.Run("NOT IN with string values", func(t *testing.T) {
 		t.Parallel()
 		result, err := ParseFilter("role NOT IN ('admin', 'superadmin')")
@@ -560,6 +759,83 @@ func TestParseFilter_Arrays(t *testing.T) {
 		assert.NotNil(t, comparison.Right.Array.Values[1].Number)
 		assert.NotNil(t, comparison.Right.Array.Values[2].Int)
 	})
+
+	t.Run("IN with null value", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("status IN ('active', null, NULL)")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+
+		assert.Len(t, comparison.Right.Array.Values, 3)
+		assert.False(t, comparison.Right.Array.Values[0].Null)
+		assert.True(t, comparison.Right.Array.Values[1].Null)
+		assert.True(t, comparison.Right.Array.Values[2].Null)
+
+		value, kind := comparison.Right.Array.Values[1].Resolve()
+		assert.Nil(t, value)
+		assert.Equal(t, KindInvalid, kind)
+	})
+}
+
+func TestParseFilter_Ranges(t *testing.T) {
+	t.Parallel()
+
+	t.Run("integer range", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("age=18..65")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+
+		assert.True(t, comparison.Op.Equal)
+		require.NotNil(t, comparison.Right.Int)
+		require.NotNil(t, comparison.To.Int)
+		assert.Equal(t, 18, *comparison.Right.Int)
+		assert.Equal(t, 65, *comparison.To.Int)
+	})
+
+	t.Run("float range", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("price=1.5..9.99")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+
+		require.NotNil(t, comparison.Right.Number)
+		require.NotNil(t, comparison.To.Number)
+		assert.Equal(t, 1.5, *comparison.Right.Number)
+		assert.Equal(t, 9.99, *comparison.To.Number)
+	})
+
+	t.Run("negative and date bounds", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("delta=-5..-1 && created_at=2024-01-01..2024-12-31")
+
+		require.NoError(t, err)
+		delta := result.Expression.And[0].Comparison[0]
+		created := result.Expression.And[0].Comparison[1]
+
+		assert.Equal(t, -5, *delta.Right.Int)
+		assert.Equal(t, -1, *delta.To.Int)
+		assert.NotNil(t, created.Right.Date)
+		assert.NotNil(t, created.To.Date)
+	})
+
+	t.Run("plain values have no upper bound", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("age=18")
+
+		require.NoError(t, err)
+		assert.Nil(t, result.Expression.And[0].Comparison[0].To)
+	})
+
+	t.Run("missing upper bound fails", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseFilter("age=18..")
+
+		require.Error(t, err)
+	})
 }
 
 func TestParseFilter_ErrorCases(t *testing.T) {
@@ -608,15 +884,23 @@ func TestOperator_String(t *testing.T) {
 	}{
 		{"equal", Operator{Equal: true}, "="},
 		{"not equal", Operator{NotEqual: true}, "!="},
+		{"null-safe equal", Operator{NullSafeEqual: true}, "<=>"},
 		{"greater", Operator{Greater: true}, ">"},
 		{"less", Operator{Less: true}, "<"},
 		{"greater or equal", Operator{GreaterOrEqual: true}, ">="},
 		{"less or equal", Operator{LessOrEqual: true}, "<="},
 		{"like", Operator{Like: true}, "LIKE"},
 		{"not like", Operator{NotLike: true}, "NOT LIKE"},
+		{"ilike", Operator{ILike: true}, "ILIKE"},
+		{"not ilike", Operator{NotILike: true}, "NOT ILIKE"},
 		{"in", Operator{In: true}, "IN"},
 		{"not in", Operator{NotIn: true}, "NOT IN"},
+		{"is distinct from", Operator{DistinctFrom: true}, "IS DISTINCT FROM"},
+		{"is not distinct from", Operator{NotDistinctFrom: true}, "IS NOT DISTINCT FROM"},
 		{"is", Operator{Is: true}, "IS"},
+		{"contains", Operator{Contains: true}, "@>"},
+		{"regexp", Operator{Regexp: true}, "REGEXP"},
+		{"not regexp", Operator{NotRegexp: true}, "NOT REGEXP"},
 		{"empty operator", Operator{}, ""},
 	}
 
@@ -651,6 +935,48 @@ func TestBoolean_Value(t *testing.T) {
 	})
 }
 
+func TestValue_Resolve(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		filter        string
+		expectedValue any
+		expectedKind  ValueKind
+	}{
+		{"single-quoted string", "name='John'", "John", KindString},
+		{"double-quoted string", `name="John"`, "John", KindString},
+		{"int", "age=18", 18, KindInt},
+		{"float", "price=19.99", 19.99, KindFloat},
+		{"bool", "active=true", true, KindBool},
+		{"date", "created_at>2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), KindDate},
+		{"array", "id IN (1, 'two', 3.5)", []any{1, "two", 3.5}, KindArray},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			value, kind := result.Expression.And[0].Comparison[0].Right.Resolve()
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedKind, kind)
+		})
+	}
+
+	t.Run("nil value is invalid", func(t *testing.T) {
+		t.Parallel()
+		var v *Value
+
+		value, kind := v.Resolve()
+		assert.Nil(t, value)
+		assert.Equal(t, KindInvalid, kind)
+		assert.Equal(t, "invalid", kind.String())
+	})
+}
+
 func TestParseFilter_EdgeCases(t *testing.T) {
 	t.Parallel()
 
@@ -672,6 +998,15 @@ func TestParseFilter_EdgeCases(t *testing.T) {
 		assert.Equal(t, "field123", comparison.Left.Field)
 	})
 
+	t.Run("table-qualified field name", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("profiles.verified=true")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.Equal(t, "profiles.verified", comparison.Left.Field)
+	})
+
 	t.Run("multiple spaces between tokens", func(t *testing.T) {
 		t.Parallel()
 		result, err := ParseFilter("age   >   18")
@@ -759,6 +1094,183 @@ func TestParseFilter_EdgeCases(t *testing.T) {
 	})
 }
 
+func TestParseFilter_BarePredicates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bare field", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("active")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.Equal(t, "active", comparison.Left.Field)
+		assert.False(t, comparison.Not)
+		assert.Nil(t, comparison.Op)
+		assert.Nil(t, comparison.Right)
+	})
+
+	t.Run("negated bare field", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("!active")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.Equal(t, "active", comparison.Left.Field)
+		assert.True(t, comparison.Not)
+	})
+
+	t.Run("bare field combined with comparison", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("active && age>18")
+
+		require.NoError(t, err)
+		require.Len(t, result.Expression.And[0].Comparison, 2)
+		assert.Equal(t, "active", result.Expression.And[0].Comparison[0].Left.Field)
+		assert.True(t, result.Expression.And[0].Comparison[1].Op.Greater)
+	})
+
+	t.Run("not equal is not confused with negation", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("status!='active'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.False(t, comparison.Not)
+		assert.True(t, comparison.Op.NotEqual)
+	})
+}
+
+func TestParseFilter_Dates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("date literal", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("created_at>2024-01-01")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		require.NotNil(t, comparison.Right.Date)
+		assert.True(t, comparison.Right.Date.DateOnly)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), comparison.Right.Date.Time)
+		assert.Equal(t, "2024-01-01", comparison.Right.Date.String())
+	})
+
+	t.Run("datetime with timezone", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("created_at>=2024-01-01T10:30:00-03:00")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		require.NotNil(t, comparison.Right.Date)
+		assert.False(t, comparison.Right.Date.DateOnly)
+		assert.True(t, comparison.Right.Date.Time.Equal(time.Date(2024, 1, 1, 13, 30, 0, 0, time.UTC)))
+		assert.Equal(t, "2024-01-01T10:30:00-03:00", comparison.Right.Date.String())
+	})
+
+	t.Run("datetime in UTC", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("created_at<2024-06-30T23:59:59Z")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		require.NotNil(t, comparison.Right.Date)
+		assert.Equal(t, "2024-06-30T23:59:59Z", comparison.Right.Date.String())
+	})
+
+	t.Run("quoted date stays a string", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("created_at>'2024-01-01'")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.Nil(t, comparison.Right.Date)
+		assert.NotNil(t, comparison.Right.String)
+	})
+
+	t.Run("invalid date is rejected", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("created_at>2024-13-45")
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "invalid filter syntax")
+	})
+}
+
+func TestParseFilter_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("modulo with literal", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("id % 2 = 0")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		assert.Equal(t, "id", comparison.Left.Field)
+		require.Len(t, comparison.Left.Arith, 1)
+		assert.Equal(t, "%", comparison.Left.Arith[0].Op)
+		assert.Equal(t, "2", comparison.Left.Arith[0].Operand())
+		assert.True(t, comparison.Op.Equal)
+		assert.Equal(t, 0, *comparison.Right.Int)
+	})
+
+	t.Run("multiplication with field", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("price * quantity > 1000")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		require.Len(t, comparison.Left.Arith, 1)
+		assert.Equal(t, "quantity", comparison.Left.Arith[0].Field)
+		assert.Equal(t, []string{"price", "quantity"}, comparison.Left.Fields())
+	})
+
+	t.Run("chained operations", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("price * 2 + tax >= 100")
+
+		require.NoError(t, err)
+		comparison := result.Expression.And[0].Comparison[0]
+		require.Len(t, comparison.Left.Arith, 2)
+		assert.Equal(t, "+", comparison.Left.Arith[1].Op)
+	})
+
+	t.Run("minus without spaces", func(t *testing.T) {
+		t.Parallel()
+
+		for _, filter := range []string{"a-1 > 0", "a - 1 > 0", "a -1 > 0", "price*2-1.5 > 0"} {
+			result, err := ParseFilter(filter)
+
+			require.NoError(t, err, filter)
+			comparison := result.Expression.And[0].Comparison[0]
+			last := comparison.Left.Arith[len(comparison.Left.Arith)-1]
+			assert.Equal(t, "-", last.Op, filter)
+			assert.NotContains(t, last.Operand(), "-", filter)
+			assert.Equal(t, 0, *comparison.Right.Int, filter)
+		}
+	})
+
+	t.Run("signed literals keep their sign", func(t *testing.T) {
+		t.Parallel()
+
+		for _, filter := range []string{"a>-1", "a = -1", "a IS DISTINCT FROM -1", "a IN (-1, -2)", "a=-5..-1"} {
+			result, err := ParseFilter(filter)
+
+			require.NoError(t, err, filter)
+			comparison := result.Expression.And[0].Comparison[0]
+			assert.Empty(t, comparison.Left.Arith, filter)
+		}
+	})
+
+	t.Run("arithmetic without operand fails", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseFilter("price * > 100")
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
 func TestParseFilter_Empty(t *testing.T) {
 	t.Parallel()
 
@@ -770,4 +1282,211 @@ func TestParseFilter_Empty(t *testing.T) {
 		require.NoError(t, err)
 		assert.Nil(t, filter)
 	})
+
+	t.Run("whitespace only input", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := ParseFilter(" \t\n")
+
+		require.NoError(t, err)
+		assert.Nil(t, filter)
+	})
+}
+
+func TestParseFilterStrict(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty input fails", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range []string{"", " \t\n", "\uFEFF"} {
+			filter, err := ParseFilterStrict(input)
+
+			require.Error(t, err)
+			assert.Equal(t, "filter is empty", err.Error())
+			assert.Nil(t, filter)
+		}
+	})
+
+	t.Run("same AST as ParseFilter", func(t *testing.T) {
+		t.Parallel()
+
+		expected, err := ParseFilter("age>18 && status IN ('a','b')")
+		require.NoError(t, err)
+
+		filter, err := ParseFilterStrict("age>18 && status IN ('a','b')")
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, filter)
+	})
+
+	t.Run("syntax errors are returned", func(t *testing.T) {
+		t.Parallel()
+
+		p, err := NewParser()
+		require.NoError(t, err)
+
+		filter, err := p.ParseFilterStrict("age>>18")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid filter syntax")
+		assert.Nil(t, filter)
+	})
+}
+
+func TestParseFilter_Trimming(t *testing.T) {
+	t.Parallel()
+
+	t.Run("leading BOM", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := ParseFilter("\uFEFFage>18")
+
+		require.NoError(t, err)
+		assert.Equal(t, "age", filter.Expression.And[0].Comparison[0].Left.Field)
+	})
+
+	t.Run("surrounding whitespace", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := ParseFilter("  \tage>18 && status='active'\n ")
+
+		require.NoError(t, err)
+		assert.Len(t, filter.Expression.And[0].Comparison, 2)
+	})
+
+	t.Run("BOM followed by whitespace", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := ParseFilter("\uFEFF  age>18 ")
+
+		require.NoError(t, err)
+		assert.NotNil(t, filter)
+	})
+}
+
+func TestNewParser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keyword operators enabled", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewParser(WithKeywordOperators())
+		require.NoError(t, err)
+
+		result, err := p.ParseFilter("age > 18 AND status = 'active' or role = 'admin'")
+
+		require.NoError(t, err)
+		require.Len(t, result.Expression.And, 2)
+		assert.Len(t, result.Expression.And[0].Comparison, 2)
+		assert.Equal(t, "role", result.Expression.And[1].Comparison[0].Left.Field)
+	})
+
+	t.Run("keyword operators disabled by default", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewParser()
+		require.NoError(t, err)
+
+		result, err := p.ParseFilter("age > 18 AND status = 'active'")
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("keyword operators mix with symbols", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewParser(WithKeywordOperators())
+		require.NoError(t, err)
+
+		result, err := p.ParseFilter("(age > 18 and active = true) || role = 'admin'")
+
+		require.NoError(t, err)
+		require.Len(t, result.Expression.And, 2)
+		assert.NotNil(t, result.Expression.And[0].Comparison[0].Left.SubExpr)
+	})
+
+	t.Run("custom identifier pattern", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewParser(WithIdentPattern(`[a-zA-Z_][a-zA-Z0-9_]*(-[a-zA-Z0-9_]+)*`))
+		require.NoError(t, err)
+
+		result, err := p.ParseFilter("first-name = 'john'")
+
+		require.NoError(t, err)
+		assert.Equal(t, "first-name", result.Expression.And[0].Comparison[0].Left.Field)
+	})
+
+	t.Run("invalid identifier pattern", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewParser(WithIdentPattern(`[a-z`))
+
+		require.Error(t, err)
+		assert.Nil(t, p)
+	})
+}
+
+func TestNewParser_BracketOperators(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewParser(WithBracketOperators())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		bracket   string
+		canonical string
+	}{
+		{name: "gte", bracket: "age[gte]:18", canonical: "age >= 18"},
+		{name: "lt", bracket: "price[lt]:9.5", canonical: "price < 9.5"},
+		{name: "like", bracket: "name[like]:'%john%'", canonical: "name LIKE '%john%'"},
+		{name: "in", bracket: "status[in]:('active','pending')", canonical: "status IN ('active','pending')"},
+		{name: "not in", bracket: "id[nin]:(1,2)", canonical: "id NOT IN (1,2)"},
+		{name: "is null", bracket: "deleted_at[is]:null", canonical: "deleted_at IS NULL"},
+		{name: "uppercase name", bracket: "age[GTE]:18", canonical: "age >= 18"},
+		{name: "combined", bracket: "age[gte]:18 && (role[eq]:'admin' || name[ilike]:'a%')", canonical: "age >= 18 && (role = 'admin' || name ILIKE 'a%')"},
+		{name: "brackets inside strings are kept", bracket: "name[eq]:'[gte]:x'", canonical: "name = '[gte]:x'"},
+		{name: "symbolic syntax still works", bracket: "age>=18", canonical: "age >= 18"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := ParseFilter(tc.canonical)
+			require.NoError(t, err)
+
+			result, err := p.ParseFilter(tc.bracket)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+
+	errorTests := []struct {
+		name   string
+		filter string
+		errMsg string
+	}{
+		{name: "unknown operator", filter: "age[between]:1", errMsg: "unknown operator 'between'"},
+		{name: "missing colon", filter: "age[gte]18", errMsg: "expected ':' after '[gte]'"},
+		{name: "unclosed bracket", filter: "age[gte:18", errMsg: "unclosed '['"},
+	}
+
+	for _, tc := range errorTests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := p.ParseFilter(tc.filter)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+			assert.Nil(t, result)
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseFilter("age[gte]:18")
+		require.Error(t, err)
+	})
 }
@@ -0,0 +1,79 @@
+package parser
+
+// ValueKind identifies the Go type of a resolved Value.
+type ValueKind int
+
+const (
+	// KindInvalid is an empty value.
+	KindInvalid ValueKind = iota
+	// KindString is a string value, without quotes.
+	KindString
+	// KindInt is an int value.
+	KindInt
+	// KindFloat is a float64 value.
+	KindFloat
+	// KindBool is a bool value.
+	KindBool
+	// KindDate is a time.Time value.
+	KindDate
+	// KindArray is a []any value of resolved elements.
+	KindArray
+)
+
+// String returns the kind name.
+func (k ValueKind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindDate:
+		return "date"
+	case KindArray:
+		return "array"
+	default:
+		return "invalid"
+	}
+}
+
+// Resolve returns the Go-typed value and its kind: a string without quotes,
+// int, float64, bool, time.Time, or []any for arrays.
+func (v *Value) Resolve() (any, ValueKind) {
+	if v == nil {
+		return nil, KindInvalid
+	}
+
+	switch {
+	case v.Date != nil:
+		return v.Date.Time, KindDate
+	case v.String != nil:
+		return Unquote(*v.String), KindString
+	case v.Int != nil:
+		return *v.Int, KindInt
+	case v.Number != nil:
+		return *v.Number, KindFloat
+	case v.Boolean != nil:
+		return v.Boolean.Value(), KindBool
+	case v.Array != nil:
+		values := make([]any, 0, len(v.Array.Values))
+		for _, val := range v.Array.Values {
+			resolved, _ := val.Resolve()
+			values = append(values, resolved)
+		}
+		return values, KindArray
+	default:
+		return nil, KindInvalid
+	}
+}
+
+// Unquote removes the surrounding single or double quotes from a string literal.
+func Unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
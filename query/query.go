@@ -1,6 +1,9 @@
 package query
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/url"
 	"strconv"
 	"strings"
@@ -11,18 +14,47 @@ import (
 
 // Params holds parsed query parameters.
 type Params struct {
-	Fields []string
-	Filter string
-	Sort   []string
-	Limit  int
-	Offset int
+	Fields []string `json:"fields"`
+	Filter string   `json:"filter"`
+	Sort   []string `json:"sort"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+	Cursor string   `json:"cursor"`
+	Group  []string `json:"group"`
 }
 
 // Parse parses URL query parameters and returns a QueryBuilder.
 // Validation is optional - use QueryBuilder.Validate() to enable it.
 func Parse(params url.Values, table string) (*builder.QueryBuilder, error) {
-	// Parse query parameters
-	qp := parseQueryParams(params)
+	return build(parseQueryParams(params), table)
+}
+
+// ParseJSON parses query parameters from a JSON body and returns a QueryBuilder.
+// The body uses the same keys as the URL query parameters, with fields and
+// sort given as arrays:
+//
+//	{"filter": "age>18", "fields": ["id", "name"], "sort": ["-id"], "limit": 10}
+func ParseJSON(body io.Reader, table string) (*builder.QueryBuilder, error) {
+	var qp Params
+	if err := json.NewDecoder(body).Decode(&qp); err != nil {
+		return nil, fmt.Errorf("invalid JSON query body: %w", err)
+	}
+	return build(&qp, table)
+}
+
+// FromParams builds a QueryBuilder from query parameters that are already
+// parsed, e.g. assembled in code or decoded from a message. The filter string
+// is parsed; other parameters are applied as given.
+//
+// Example:
+//
+//	qb, err := query.FromParams(query.Params{Filter: "age>18", Sort: []string{"-id"}, Limit: 10}, "users")
+func FromParams(params Params, table string) (*builder.QueryBuilder, error) {
+	return build(&params, table)
+}
+
+// build creates a QueryBuilder from parsed query parameters.
+func build(qp *Params, table string) (*builder.QueryBuilder, error) {
 	qb := builder.NewQueryBuilder(table)
 
 	// Parse and set filter (no validation)
@@ -35,22 +67,56 @@ func Parse(params url.Values, table string) (*builder.QueryBuilder, error) {
 		qb.SetFields(qp.Fields)
 	}
 
+	// Set group by (no validation)
+	if len(qp.Group) > 0 {
+		setGroupBy(qb, qp.Group)
+	}
+
 	// Set sort (no validation)
 	if len(qp.Sort) > 0 {
 		qb.SetSort(qp.Sort)
 	}
 
-	// Set pagination
-	if qp.Limit > 0 {
+	// Set pagination. Negative values are kept so the validator can reject them.
+	if qp.Limit != 0 {
 		qb.SetLimit(qp.Limit)
 	}
-	if qp.Offset > 0 {
+	if qp.Offset != 0 {
 		qb.SetOffset(qp.Offset)
 	}
 
+	// A cursor replaces the offset and, when it carries one, the limit
+	if qp.Cursor != "" {
+		cursor, err := builder.DecodeCursor(qp.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		qb.SetCursor(cursor)
+	}
+
 	return qb, nil
 }
 
+// rollupPrefix marks a group list wrapped in ROLLUP, e.g.
+// "group=rollup:category,status".
+const rollupPrefix = "rollup:"
+
+// setGroupBy sets the GROUP BY fields, using ROLLUP when the first field
+// carries the "rollup:" prefix (any case).
+func setGroupBy(qb *builder.QueryBuilder, group []string) {
+	first := group[0]
+	if len(first) < len(rollupPrefix) || !strings.EqualFold(first[:len(rollupPrefix)], rollupPrefix) {
+		qb.SetGroupBy(group)
+		return
+	}
+
+	fields := make([]string, 0, len(group))
+	if field := strings.TrimSpace(first[len(rollupPrefix):]); field != "" {
+		fields = append(fields, field)
+	}
+	qb.SetGroupByRollup(append(fields, group[1:]...))
+}
+
 // parseAndSetFilter parses the filter and sets it in the query builder.
 func parseAndSetFilter(qb *builder.QueryBuilder, filter string) error {
 	if filter == "" {
@@ -67,15 +133,36 @@ func parseAndSetFilter(qb *builder.QueryBuilder, filter string) error {
 }
 
 // parseCommaSeparatedList splits a comma-separated string and trims each value.
+// Commas inside parentheses (e.g. "COALESCE(a,b)") do not split.
 func parseCommaSeparatedList(value string) []string {
 	if value == "" {
 		return nil
 	}
-	parts := strings.Split(value, ",")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(parts[i])
+	parts := make([]string, 0, strings.Count(value, ",")+1)
+	depth, start := 0, 0
+	for i, c := range value {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(value[start:]))
+}
+
+// parseLimitParam parses the limit parameter, mapping "all" (any case) to
+// builder.LimitAll.
+func parseLimitParam(params url.Values) int {
+	if strings.EqualFold(params.Get("limit"), "all") {
+		return builder.LimitAll
 	}
-	return parts
+	return parseIntParam(params, "limit")
 }
 
 // parseIntParam parses an integer parameter from url.Values.
@@ -94,7 +181,9 @@ func parseQueryParams(params url.Values) *Params {
 		Fields: parseCommaSeparatedList(params.Get("fields")),
 		Filter: params.Get("filter"),
 		Sort:   parseCommaSeparatedList(params.Get("sort")),
-		Limit:  parseIntParam(params, "limit"),
+		Limit:  parseLimitParam(params),
 		Offset: parseIntParam(params, "offset"),
+		Cursor: params.Get("cursor"),
+		Group:  parseCommaSeparatedList(params.Get("group")),
 	}
 }
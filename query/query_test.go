@@ -2,6 +2,7 @@ package query
 
 import (
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -265,6 +266,12 @@ func TestParseCommaSeparatedList(t *testing.T) {
 		assert.Equal(t, "j", result[9])
 	})
 
+	t.Run("commas inside parentheses do not split", func(t *testing.T) {
+		t.Parallel()
+		result := parseCommaSeparatedList("-COALESCE(updated_at, created_at),name")
+		assert.Equal(t, []string{"-COALESCE(updated_at, created_at)", "name"}, result)
+	})
+
 	t.Run("values with underscores and numbers", func(t *testing.T) {
 		t.Parallel()
 		result := parseCommaSeparatedList("user_id,created_at,field123")
@@ -422,6 +429,15 @@ func TestParseQueryParams(t *testing.T) {
 		assert.Equal(t, 0, result.Limit)
 	})
 
+	t.Run("limit=all maps to LimitAll", func(t *testing.T) {
+		t.Parallel()
+		params, _ := url.ParseQuery("limit=all")
+
+		result := parseQueryParams(params)
+
+		assert.Equal(t, builder.LimitAll, result.Limit)
+	})
+
 	t.Run("invalid offset is treated as zero", func(t *testing.T) {
 		t.Parallel()
 		params, _ := url.ParseQuery("offset=invalid")
@@ -709,3 +725,150 @@ func TestParams_Struct(t *testing.T) {
 		assert.Equal(t, 0, params.Offset)
 	})
 }
+
+func TestParse_SortFunctions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("COALESCE in sort parameter", func(t *testing.T) {
+		t.Parallel()
+		params, _ := url.ParseQuery("sort=-COALESCE(updated_at,created_at),id")
+
+		qb, err := Parse(params, "posts")
+		require.NoError(t, err)
+
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM posts ORDER BY COALESCE(updated_at, created_at) DESC, id ASC", sql)
+	})
+}
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("full body with all parameters", func(t *testing.T) {
+		t.Parallel()
+		body := `{"filter": "age>18", "fields": ["id", "name"], "sort": ["-created_at"], "limit": 10, "offset": 20}`
+
+		qb, err := ParseJSON(strings.NewReader(body), "users")
+		require.NoError(t, err)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name FROM users WHERE age > ? ORDER BY created_at DESC LIMIT 10 OFFSET 20", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		t.Parallel()
+
+		qb, err := ParseJSON(strings.NewReader("not json"), "users")
+		require.Error(t, err)
+		assert.Nil(t, qb)
+	})
+
+	t.Run("invalid filter syntax", func(t *testing.T) {
+		t.Parallel()
+
+		qb, err := ParseJSON(strings.NewReader(`{"filter": "age >> 18"}`), "users")
+		require.Error(t, err)
+		assert.Nil(t, qb)
+		assert.Contains(t, err.Error(), "invalid filter syntax")
+	})
+}
+
+func TestFromParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all parameters", func(t *testing.T) {
+		t.Parallel()
+		params := Params{
+			Filter: "age>18 && status='active'",
+			Fields: []string{"id", "name"},
+			Sort:   []string{"-created_at"},
+			Limit:  10,
+			Offset: 20,
+		}
+
+		qb, err := FromParams(params, "users")
+		require.NoError(t, err)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id, name FROM users WHERE (age > ? AND status = ?) ORDER BY created_at DESC LIMIT 10 OFFSET 20", sql)
+		assert.Equal(t, []any{18, "active"}, args)
+	})
+
+	t.Run("empty params", func(t *testing.T) {
+		t.Parallel()
+
+		qb, err := FromParams(Params{}, "users")
+		require.NoError(t, err)
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("invalid filter syntax", func(t *testing.T) {
+		t.Parallel()
+
+		qb, err := FromParams(Params{Filter: "age >> 18"}, "users")
+		require.Error(t, err)
+		assert.Nil(t, qb)
+		assert.Contains(t, err.Error(), "invalid filter syntax")
+	})
+
+	t.Run("validation applies", func(t *testing.T) {
+		t.Parallel()
+
+		qb, err := FromParams(Params{Fields: []string{"password"}}, "users")
+		require.NoError(t, err)
+
+		_, _, err = qb.Validate(builder.WithAllowedFields([]string{"id"})).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+}
+
+func TestParse_Cursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cursor replaces offset", func(t *testing.T) {
+		t.Parallel()
+		cursor := builder.EncodeCursor(builder.Cursor{Offset: 40})
+		params := url.Values{"sort": {"id"}, "limit": {"20"}, "offset": {"5"}, "cursor": {cursor}}
+
+		qb, err := Parse(params, "users")
+
+		require.NoError(t, err)
+		sql, _, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users ORDER BY id ASC LIMIT 20 OFFSET 40", sql)
+	})
+
+	t.Run("keyset cursor adds a predicate", func(t *testing.T) {
+		t.Parallel()
+		cursor := builder.EncodeCursor(builder.Cursor{Limit: 10, After: []any{42}})
+		params := url.Values{"filter": {"age>18"}, "sort": {"-id"}, "cursor": {cursor}}
+
+		qb, err := Parse(params, "users")
+
+		require.NoError(t, err)
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND id < ?) ORDER BY id DESC LIMIT 10", sql)
+		assert.Equal(t, []any{18, 42}, args)
+	})
+
+	t.Run("invalid cursor fails", func(t *testing.T) {
+		t.Parallel()
+		params := url.Values{"cursor": {"%%%"}}
+
+		qb, err := Parse(params, "users")
+
+		require.Error(t, err)
+		assert.Nil(t, qb)
+		assert.Contains(t, err.Error(), "invalid cursor")
+	})
+}
@@ -5,7 +5,13 @@
 package restql
 
 import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/lucasvillarinho/restql/builder"
 	"github.com/lucasvillarinho/restql/parser"
@@ -25,8 +31,90 @@ type (
 	// Filter represents the root of the filter expression tree.
 	Filter = parser.Filter
 
+	// FieldRef starts a comparison in the filter DSL. Create one with F.
+	FieldRef = parser.FieldRef
+
 	// QueryParams holds parsed query parameters.
 	QueryParams = query.Params
+
+	// Dialect identifies the SQL dialect a query is built for.
+	Dialect = builder.Dialect
+
+	// Arg is a bound argument with the kind of the value it was parsed from.
+	Arg = builder.Arg
+
+	// DebugArg pairs a bound argument with its placeholder for logging.
+	DebugArg = builder.DebugArg
+
+	// ValueKind identifies the Go type of a parsed value.
+	ValueKind = parser.ValueKind
+
+	// SortDuplicates controls how a sort that repeats a field is handled.
+	SortDuplicates = builder.SortDuplicates
+
+	// Cursor is the pagination state carried by an opaque cursor token.
+	Cursor = builder.Cursor
+
+	// Logger receives debug logs of built queries and warnings for rejected
+	// queries. *slog.Logger satisfies it.
+	Logger = builder.Logger
+
+	// Pager exposes a query's limit and offset. QueryBuilder and Validator
+	// implement it.
+	Pager = builder.Pager
+
+	// PaginationMeta describes the page of results a query returns.
+	PaginationMeta = builder.PaginationMeta
+)
+
+const (
+	// DialectGeneric emits portable SQL. This is the default.
+	DialectGeneric = builder.DialectGeneric
+
+	// DialectPostgres emits PostgreSQL-specific SQL.
+	DialectPostgres = builder.DialectPostgres
+
+	// DialectMySQL emits MySQL-specific SQL.
+	DialectMySQL = builder.DialectMySQL
+
+	// DialectSQLite emits SQLite-specific SQL.
+	DialectSQLite = builder.DialectSQLite
+
+	// DialectOracle emits Oracle-specific SQL.
+	DialectOracle = builder.DialectOracle
+
+	// DialectClickHouse emits ClickHouse-specific SQL.
+	DialectClickHouse = builder.DialectClickHouse
+
+	// DialectSQLServer emits SQL Server-specific SQL.
+	DialectSQLServer = builder.DialectSQLServer
+
+	// SortDuplicatesAllow keeps repeated sort fields as given. This is the default.
+	SortDuplicatesAllow = builder.SortDuplicatesAllow
+
+	// SortDuplicatesError rejects a sort that repeats a field.
+	SortDuplicatesError = builder.SortDuplicatesError
+
+	// SortDuplicatesKeepFirst keeps only the first occurrence of each field.
+	SortDuplicatesKeepFirst = builder.SortDuplicatesKeepFirst
+
+	// LimitAll is the limit requested by "limit=all": no LIMIT clause.
+	LimitAll = builder.LimitAll
+
+	// KindString is a string value.
+	KindString = parser.KindString
+
+	// KindInt is an int value.
+	KindInt = parser.KindInt
+
+	// KindFloat is a float64 value.
+	KindFloat = parser.KindFloat
+
+	// KindBool is a bool value.
+	KindBool = parser.KindBool
+
+	// KindDate is a time.Time value.
+	KindDate = parser.KindDate
 )
 
 // SQLBuilder represents any type that can generate SQL queries.
@@ -42,29 +130,142 @@ var (
 	// ParseFilter parses a filter string into an AST.
 	ParseFilter = parser.ParseFilter
 
+	// ParseFilterStrict parses a filter string into an AST, rejecting an empty filter.
+	ParseFilterStrict = parser.ParseFilterStrict
+
+	// AndFilters combines two filters with AND.
+	AndFilters = parser.AndFilters
+
+	// OrFilters combines two filters with OR.
+	OrFilters = parser.OrFilters
+
+	// F references a field for building a filter programmatically,
+	// e.g. restql.F("age").Gt(18).And(restql.F("status").Eq("active")).
+	F = parser.F
+
 	// Parse parses URL query parameters and returns a QueryBuilder.
 	// Validation is optional - use QueryBuilder.Validate() to enable it.
 	Parse = query.Parse
 
+	// ParseJSON parses query parameters from a JSON body and returns a QueryBuilder.
+	ParseJSON = query.ParseJSON
+
+	// FromParams builds a QueryBuilder from already parsed query parameters.
+	FromParams = query.FromParams
+
+	// EncodeCursor encodes a cursor as a URL-safe base64 token.
+	EncodeCursor = builder.EncodeCursor
+
+	// DecodeCursor decodes a token produced by EncodeCursor.
+	DecodeCursor = builder.DecodeCursor
+
+	// Paginate computes pagination metadata from a query's limit and offset
+	// and the total row count.
+	Paginate = builder.Paginate
+
+	// ToMongoFilter translates a filter into a MongoDB query document.
+	ToMongoFilter = builder.ToMongoFilter
+
+	// ToElasticQuery translates a filter into an Elasticsearch query.
+	ToElasticQuery = builder.ToElasticQuery
+
+	// ValidateFilterString parses and validates a filter without building a query.
+	ValidateFilterString = builder.ValidateFilterString
+
 	// WithAllowedFields sets the allowed fields whitelist for validation.
 	WithAllowedFields = builder.WithAllowedFields
 
+	// WithForbiddenFields sets the forbidden fields blacklist for validation.
+	WithForbiddenFields = builder.WithForbiddenFields
+
+	// WithCaseFold marks fields compared case-insensitively with = and !=.
+	WithCaseFold = builder.WithCaseFold
+
+	// WithEnsureFields always selects fields when the client lists fields.
+	WithEnsureFields = builder.WithEnsureFields
+
+	// WithTableAlias sets an alias for the table.
+	WithTableAlias = builder.WithTableAlias
+
+	// WithHasMany declares a one-to-many relationship filtered with EXISTS.
+	WithHasMany = builder.WithHasMany
+
+	// WithJSONColumns declares JSON columns filtered by dotted paths.
+	WithJSONColumns = builder.WithJSONColumns
+
+	// WithCaseInsensitiveFields enables case-insensitive field matching.
+	WithCaseInsensitiveFields = builder.WithCaseInsensitiveFields
+
+	// WithStripDisallowedFields drops disallowed fields instead of rejecting the query.
+	WithStripDisallowedFields = builder.WithStripDisallowedFields
+
+	// WithSnakeCaseFields converts camelCase fields to snake_case.
+	WithSnakeCaseFields = builder.WithSnakeCaseFields
+
+	// WithRequireFilter requires at least one filter condition.
+	WithRequireFilter = builder.WithRequireFilter
+
+	// WithFieldOperators restricts the filter operators allowed per field.
+	WithFieldOperators = builder.WithFieldOperators
+
+	// WithAllowedValues restricts the values a field may be compared with.
+	WithAllowedValues = builder.WithAllowedValues
+
+	// WithAllowedOperators restricts the filter operators allowed on any field.
+	WithAllowedOperators = builder.WithAllowedOperators
+
+	// WithForbiddenOperators rejects filters using any of the given operators.
+	WithForbiddenOperators = builder.WithForbiddenOperators
+
+	// WithColumnMapper translates API field names to columns programmatically.
+	WithColumnMapper = builder.WithColumnMapper
+
+	// WithFieldTypes declares field types used to coerce quoted numbers.
+	WithFieldTypes = builder.WithFieldTypes
+
 	// WithMaxLimit sets the maximum allowed limit value.
 	WithMaxLimit = builder.WithMaxLimit
 
+	// WithUnboundedLimit accepts "limit=all" and emits no LIMIT clause.
+	WithUnboundedLimit = builder.WithUnboundedLimit
+
 	// WithMaxOffset sets the maximum allowed offset value.
 	WithMaxOffset = builder.WithMaxOffset
+
+	// WithMaxFields sets the maximum number of selected fields.
+	WithMaxFields = builder.WithMaxFields
+
+	// WithMaxSortFields sets the maximum number of sort fields.
+	WithMaxSortFields = builder.WithMaxSortFields
+
+	// WithMaxArgs sets the maximum number of bound arguments.
+	WithMaxArgs = builder.WithMaxArgs
+
+	// WithSortDuplicates sets how a sort that repeats a field is handled.
+	WithSortDuplicates = builder.WithSortDuplicates
+
+	// WithSortCollations sets the collation emitted for sort fields.
+	WithSortCollations = builder.WithSortCollations
+
+	// WithSortOrders declares custom sort orders for "field:custom" sorts.
+	WithSortOrders = builder.WithSortOrders
+
+	// WithDefaultSort sets a stable default sort used as a tiebreaker.
+	WithDefaultSort = builder.WithDefaultSort
 )
 
 // Option is a function that configures a RestQL instance.
 // Used for global application-level settings like SQL dialect, placeholder style, etc.
 type Option func(*RestQL)
 
-// WithPlaceholder sets the SQL placeholder style.
+// WithPlaceholder sets the SQL placeholder style, overriding the dialect's
+// default (see WithDialect).
 // Common values:
 //   - "?" for MySQL, SQLite (default)
 //   - "$1" for PostgreSQL (numbered placeholders)
 //   - ":1" for Oracle (numbered placeholders)
+//   - "@p1" for SQL Server (numbered placeholders)
+//   - ":p0" for Oracle/database/sql named parameters (use ToNamedSQL)
 //
 // Example:
 //
@@ -75,11 +276,194 @@ func WithPlaceholder(style string) Option {
 	}
 }
 
+// WithDialect sets the SQL dialect, which configures in one call:
+//   - placeholders: "$1" for DialectPostgres, "@p1" for DialectSQLServer,
+//     ":1" for DialectOracle and "?" otherwise
+//   - identifier quoting for DialectPostgres, DialectMySQL, DialectSQLite and
+//     DialectSQLServer, with the dialect's quote character
+//   - pagination: LIMIT/OFFSET, or OFFSET/FETCH NEXT for DialectSQLServer
+//   - operators: ILIKE is emitted natively for DialectPostgres and translated
+//     to LOWER(field) LIKE LOWER(?) elsewhere; DialectMySQL, DialectSQLite and
+//     DialectSQLServer bind boolean values as 1/0
+//
+// WithPlaceholder, WithQuotedIdentifiers and WithUnquotedIdentifiers override
+// the dialect's defaults regardless of order.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(restql.WithDialect(restql.DialectPostgres))
+//	// SELECT * FROM "users" WHERE "age" > $1 LIMIT 10
+func WithDialect(dialect Dialect) Option {
+	return func(r *RestQL) {
+		r.dialect = dialect
+	}
+}
+
+// WithBarePredicates enables bare boolean predicates in filters.
+// A bare field such as "active" is emitted as "active = ?" with arg true,
+// and "!active" with arg false. Disabled by default.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(restql.WithBarePredicates())
+func WithBarePredicates() Option {
+	return func(r *RestQL) {
+		r.barePredicates = true
+	}
+}
+
+// WithTildeILike makes the "~" and "!~" operators case-insensitive, emitting
+// ILIKE and NOT ILIKE (translated to LOWER(field) LIKE LOWER(?) outside
+// Postgres) instead of LIKE and NOT LIKE, as in PocketBase-style APIs.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(restql.WithTildeILike())
+//	// filter=name~'jo%' -> WHERE LOWER(name) LIKE LOWER(?)
+func WithTildeILike() Option {
+	return func(r *RestQL) {
+		r.tildeILike = true
+	}
+}
+
+// WithDateStrings binds date literals as normalized strings (YYYY-MM-DD or
+// RFC 3339) instead of time.Time values. Useful for drivers that do not
+// accept time.Time arguments.
+func WithDateStrings() Option {
+	return func(r *RestQL) {
+		r.dateStrings = true
+	}
+}
+
+// WithMinimalParens omits the redundant outermost parentheses of the WHERE
+// clause, e.g. "WHERE age > ? AND status = ?". Nested groups keep their
+// parentheses so mixed AND/OR precedence is preserved.
+func WithMinimalParens() Option {
+	return func(r *RestQL) {
+		r.minimalParens = true
+	}
+}
+
+// WithFoldOrEquals folds OR-ed equalities on the same field into a single
+// IN, e.g. "status='a' || status='b'" becomes "status IN (?, ?)".
+// Repeated values are bound once.
+func WithFoldOrEquals() Option {
+	return func(r *RestQL) {
+		r.foldOrEquals = true
+	}
+}
+
+// WithQuotedIdentifiers quotes table and field names with the dialect's quote
+// character: backticks for DialectMySQL and DialectClickHouse, brackets for
+// DialectSQLServer, double quotes otherwise.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(
+//	    restql.WithDialect(restql.DialectClickHouse),
+//	    restql.WithQuotedIdentifiers(),
+//	)
+func WithQuotedIdentifiers() Option {
+	return func(r *RestQL) {
+		quote := true
+		r.quoteIdentifiers = &quote
+	}
+}
+
+// WithUnquotedIdentifiers disables the identifier quoting enabled by
+// WithDialect, e.g. for schemas with mixed-case unquoted names.
+func WithUnquotedIdentifiers() Option {
+	return func(r *RestQL) {
+		quote := false
+		r.quoteIdentifiers = &quote
+	}
+}
+
+// WithArrayParams binds IN lists as a single array argument for the Postgres
+// dialect: IN becomes "field = ANY($1)" and NOT IN "field != ALL($1)".
+// Other dialects keep expanded placeholders.
+func WithArrayParams() Option {
+	return func(r *RestQL) {
+		r.arrayParams = true
+	}
+}
+
+// WithNullSafeNotIn keeps rows where the field is NULL in NOT IN filters:
+// "status NOT IN ('a','b')" is emitted as "(status NOT IN (?, ?) OR status IS
+// NULL)". Standard SQL excludes those rows, which is the default.
+func WithNullSafeNotIn() Option {
+	return func(r *RestQL) {
+		r.nullSafeNotIn = true
+	}
+}
+
+// WithAllowedTables restricts the tables queries can be built for. Parse and
+// FromRequest return an error for any other table, which guards handlers that
+// derive the table name from the route. All tables are allowed by default.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(restql.WithAllowedTables("users", "orders"))
+func WithAllowedTables(tables ...string) Option {
+	return func(r *RestQL) {
+		if r.allowedTables == nil {
+			r.allowedTables = make(map[string]bool)
+		}
+		for _, table := range tables {
+			r.allowedTables[table] = true
+		}
+	}
+}
+
+// knownParams are the query parameters RestQL reads.
+var knownParams = []string{"filter", "fields", "sort", "limit", "offset", "cursor", "group"}
+
+// WithStrictParams makes Parse and FromRequest reject URL query parameters
+// other than filter, fields, sort, limit, offset, cursor, group and the given
+// extra keys, so typos such as "fitler" are reported instead of silently ignored.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(restql.WithStrictParams("page_token"))
+func WithStrictParams(extra ...string) Option {
+	return func(r *RestQL) {
+		r.strictParams = make(map[string]bool, len(knownParams)+len(extra))
+		for _, key := range append(slices.Clone(knownParams), extra...) {
+			r.strictParams[key] = true
+		}
+	}
+}
+
+// WithLogger logs every query handled by the instance: the parsed params and
+// the generated SQL and args at debug level, and rejected queries (invalid
+// params, disallowed tables, validation failures) at warn level.
+//
+// Example:
+//
+//	rql := restql.NewRestQL(restql.WithLogger(slog.Default()))
+func WithLogger(logger Logger) Option {
+	return func(r *RestQL) {
+		r.logger = logger
+	}
+}
+
 // RestQL holds global configuration for query parsing.
 // Use NewRestQL to create an instance with default options that can be
 // reused across multiple Parse calls.
 type RestQL struct {
-	placeholderStyle string // Placeholder style: "?" (MySQL/SQLite), "$1" (PostgreSQL), ":1" (Oracle)
+	placeholderStyle string // Placeholder style; empty uses the dialect's default
+	barePredicates   bool   // Treat bare fields as boolean predicates
+	tildeILike       bool   // Emit ~ and !~ as ILIKE
+	dialect          Dialect
+	dateStrings      bool  // Bind date literals as strings
+	minimalParens    bool  // Omit redundant outermost WHERE parentheses
+	foldOrEquals     bool  // Fold same-field OR equalities into IN
+	arrayParams      bool  // Bind IN lists as a single array (Postgres)
+	nullSafeNotIn    bool  // Keep NULL rows in NOT IN
+	quoteIdentifiers *bool // Quote table and field names; nil uses the dialect's default
+	allowedTables    map[string]bool
+	strictParams     map[string]bool // Accepted query parameter keys; nil accepts any
+	logger           Logger
 }
 
 // NewRestQL creates a new RestQL instance with global configuration options.
@@ -96,9 +480,7 @@ type RestQL struct {
 //	    restql.WithMaxLimit(100),
 //	)
 func NewRestQL(opts ...Option) *RestQL {
-	rql := &RestQL{
-		placeholderStyle: "?", // Default to MySQL/SQLite style
-	}
+	rql := &RestQL{}
 
 	for _, opt := range opts {
 		opt(rql)
@@ -122,20 +504,256 @@ func NewRestQL(opts ...Option) *RestQL {
 //	)
 //	sql, args, err := query.ToSQL()
 func (r *RestQL) Parse(params url.Values, table string, opts ...ValidateOption) (SQLBuilder, error) {
+	return r.ParseContext(context.Background(), params, table, opts...)
+}
+
+// ParseContext is like Parse but aborts with the context's error when ctx is
+// done before parsing starts or before validation is configured, so a
+// cancelled HTTP request stops work on large filters.
+//
+// Example:
+//
+//	query, err := rql.ParseContext(r.Context(), r.URL.Query(), "users",
+//	    restql.WithAllowedFields([]string{"id", "name"}),
+//	)
+func (r *RestQL) ParseContext(ctx context.Context, params url.Values, table string, opts ...ValidateOption) (SQLBuilder, error) {
+	if r.logger != nil {
+		r.logger.Debug("restql: parsing query", "table", table, "params", params)
+	}
+
+	query, err := r.parseContext(ctx, params, table, opts...)
+	if err != nil {
+		r.logRejection(table, err)
+		return nil, err
+	}
+	return query, nil
+}
+
+// parseContext parses URL query parameters for ParseContext.
+func (r *RestQL) parseContext(ctx context.Context, params url.Values, table string, opts ...ValidateOption) (SQLBuilder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.checkTable(table); err != nil {
+		return nil, err
+	}
+	if err := r.checkParams(params); err != nil {
+		return nil, err
+	}
+
 	// Parse query parameters using the query package
 	qb, err := query.Parse(params, table)
 	if err != nil {
 		return nil, err
 	}
 
+	return r.configureContext(ctx, qb, opts...)
+}
+
+// FromParams builds a SQLBuilder from already parsed query parameters, applying
+// the global configuration and optional validation like Parse. Parameter keys
+// are not checked by WithStrictParams.
+//
+// Example:
+//
+//	query, err := rql.FromParams(restql.QueryParams{Filter: "age>18", Limit: 10}, "users",
+//	    restql.WithMaxLimit(100),
+//	)
+func (r *RestQL) FromParams(params QueryParams, table string, opts ...ValidateOption) (SQLBuilder, error) {
+	if err := r.checkTable(table); err != nil {
+		r.logRejection(table, err)
+		return nil, err
+	}
+
+	qb, err := query.FromParams(params, table)
+	if err != nil {
+		r.logRejection(table, err)
+		return nil, err
+	}
+	return r.configure(qb, opts...), nil
+}
+
+// FromRequest parses query parameters from an HTTP request and returns a SQLBuilder
+// with optional validation.
+// POST requests with a JSON content type are parsed from the body using ParseJSON;
+// all other requests are parsed from the URL query string.
+// Parsing is aborted when the request's context is cancelled.
+//
+// Example:
+//
+//	func listUsers(w http.ResponseWriter, r *http.Request) {
+//	    query, err := rql.FromRequest(r, "users",
+//	        restql.WithAllowedFields([]string{"id", "name"}),
+//	    )
+//	    sql, args, err := query.ToSQL()
+//	}
+func (r *RestQL) FromRequest(req *http.Request, table string, opts ...ValidateOption) (SQLBuilder, error) {
+	ctx := req.Context()
+	if req.Method == http.MethodPost && isJSON(req) {
+		if r.logger != nil {
+			r.logger.Debug("restql: parsing JSON query", "table", table)
+		}
+
+		query, err := r.parseJSONContext(ctx, req, table, opts...)
+		if err != nil {
+			r.logRejection(table, err)
+			return nil, err
+		}
+		return query, nil
+	}
+
+	return r.ParseContext(ctx, req.URL.Query(), table, opts...)
+}
+
+// parseJSONContext parses a JSON request body for FromRequest.
+func (r *RestQL) parseJSONContext(ctx context.Context, req *http.Request, table string, opts ...ValidateOption) (SQLBuilder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.checkTable(table); err != nil {
+		return nil, err
+	}
+	qb, err := query.ParseJSON(req.Body, table)
+	if err != nil {
+		return nil, err
+	}
+	return r.configureContext(ctx, qb, opts...)
+}
+
+// logRejection logs a query rejected while parsing, when a logger is set.
+func (r *RestQL) logRejection(table string, err error) {
+	if r.logger != nil {
+		r.logger.Warn("restql: query rejected", "table", table, "error", err)
+	}
+}
+
+// Preset is a RestQL bound to a table and a set of validation options, so an
+// endpoint's rules are declared once and reused for every request.
+type Preset struct {
+	rql   *RestQL
+	table string
+	opts  []ValidateOption
+}
+
+// NewPreset returns a Preset that parses queries for table with opts.
+//
+// Example:
+//
+//	users := rql.NewPreset("users",
+//	    restql.WithAllowedFields([]string{"id", "name", "email"}),
+//	    restql.WithMaxLimit(100),
+//	)
+//
+//	e.GET("/users", func(c echo.Context) error {
+//	    query, err := users.Parse(c.QueryParams())
+//	    ...
+//	})
+func (r *RestQL) NewPreset(table string, opts ...ValidateOption) *Preset {
+	return &Preset{rql: r, table: table, opts: slices.Clone(opts)}
+}
+
+// Parse parses URL query parameters with the preset's table and options.
+func (p *Preset) Parse(params url.Values) (SQLBuilder, error) {
+	return p.rql.Parse(params, p.table, p.opts...)
+}
+
+// FromRequest parses an HTTP request with the preset's table and options.
+// See RestQL.FromRequest.
+func (p *Preset) FromRequest(req *http.Request) (SQLBuilder, error) {
+	return p.rql.FromRequest(req, p.table, p.opts...)
+}
+
+// NewQueryBuilder returns a QueryBuilder bound to the preset's table, with the
+// RestQL instance's settings applied, for queries assembled in code. Pass it to
+// Validate to apply the preset's validation options.
+//
+// Example:
+//
+//	qb := users.NewQueryBuilder()
+//	qb.SetFilter(restql.F("age").Gt(18))
+//	sql, args, err := users.Validate(qb).ToSQL()
+func (p *Preset) NewQueryBuilder() *QueryBuilder {
+	qb := builder.NewQueryBuilder(p.table)
+	p.rql.configure(qb)
+	return qb
+}
+
+// Validate applies the preset's validation options to qb.
+func (p *Preset) Validate(qb *QueryBuilder) *Validator {
+	return qb.Validate(p.opts...)
+}
+
+// checkTable returns an error if table, trimmed like NewQueryBuilder does,
+// is not in the allowed tables.
+func (r *RestQL) checkTable(table string) error {
+	if len(r.allowedTables) > 0 && !r.allowedTables[strings.TrimSpace(table)] {
+		return fmt.Errorf("table '%s' is not allowed", table)
+	}
+	return nil
+}
+
+// checkParams returns an error listing the unknown keys in params when strict
+// params are enabled.
+func (r *RestQL) checkParams(params url.Values) error {
+	if r.strictParams == nil {
+		return nil
+	}
+
+	var unknown []string
+	for key := range params {
+		if !r.strictParams[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown)
+	return fmt.Errorf("unknown query parameters: %s", strings.Join(unknown, ", "))
+}
+
+// isJSON reports whether the request has a JSON content type.
+func isJSON(req *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// configureContext is like configure but first checks that ctx is not done.
+func (r *RestQL) configureContext(ctx context.Context, qb *QueryBuilder, opts ...ValidateOption) (SQLBuilder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.configure(qb, opts...), nil
+}
+
+// configure applies the global configuration and validation options to a QueryBuilder.
+func (r *RestQL) configure(qb *QueryBuilder, opts ...ValidateOption) SQLBuilder {
 	// Apply global configuration
-	qb.SetPlaceholder(r.placeholderStyle)
+	placeholder := r.placeholderStyle
+	if placeholder == "" {
+		placeholder = r.dialect.Placeholder()
+	}
+	qb.SetPlaceholder(placeholder)
+	qb.SetBarePredicates(r.barePredicates)
+	qb.SetTildeILike(r.tildeILike)
+	qb.SetDialect(r.dialect)
+	qb.SetDateStrings(r.dateStrings)
+	qb.SetMinimalParens(r.minimalParens)
+	qb.SetFoldOrEquals(r.foldOrEquals)
+	qb.SetArrayParams(r.arrayParams)
+	qb.SetNullSafeNotIn(r.nullSafeNotIn)
+	quote := r.dialect.QuotesIdentifiers()
+	if r.quoteIdentifiers != nil {
+		quote = *r.quoteIdentifiers
+	}
+	qb.SetQuoteIdentifiers(quote)
+	qb.SetLogger(r.logger)
 
 	// If validation options are provided, apply them
 	if len(opts) > 0 {
-		validator := qb.Validate(opts...)
-		return validator, nil
+		return qb.Validate(opts...)
 	}
 
-	return qb, nil
+	return qb
 }
@@ -1,13 +1,21 @@
 package restql_test
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/lucasvillarinho/restql"
+	"github.com/lucasvillarinho/restql/parser"
+	"github.com/lucasvillarinho/restql/query"
 )
 
 func TestNewRestQL(t *testing.T) {
@@ -49,6 +57,38 @@ func TestRestQL_Parse(t *testing.T) {
 		assert.Contains(t, sql, "LIMIT 50")
 	})
 
+	t.Run("rejects negative limit and offset", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		params, err := url.ParseQuery("limit=-5&offset=-1")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "users",
+			restql.WithMaxLimit(100),
+		)
+		require.NoError(t, err)
+
+		_, _, err = query.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "limit -5 must not be negative")
+	})
+
+	t.Run("counts fields after splitting", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		params, err := url.ParseQuery("fields=id, name ,email")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "users", restql.WithMaxFields(2))
+		require.NoError(t, err)
+
+		_, _, err = query.ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 fields requested exceeds maximum allowed of 2")
+	})
+
 	t.Run("applies validation options in Parse", func(t *testing.T) {
 		t.Parallel()
 		rql := restql.NewRestQL()
@@ -208,6 +248,55 @@ func TestRestQL_Parse(t *testing.T) {
 	})
 }
 
+func TestRestQL_ParseContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cancelled context returns the context error", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		params, err := url.ParseQuery("filter=age>18")
+		require.NoError(t, err)
+
+		query, err := rql.ParseContext(ctx, params, "users",
+			restql.WithAllowedFields([]string{"age"}),
+		)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, query)
+	})
+
+	t.Run("live context parses normally", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		params, err := url.ParseQuery("filter=age>18")
+		require.NoError(t, err)
+
+		query, err := rql.ParseContext(context.Background(), params, "users")
+		require.NoError(t, err)
+
+		sql, args, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE age > ?", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("FromRequest honors the request context", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, "/users?filter=age>18", nil).WithContext(ctx)
+
+		_, err := rql.FromRequest(req, "users")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
 func TestRestQL_Compatibility(t *testing.T) {
 	t.Parallel()
 
@@ -341,3 +430,788 @@ func TestRestQL_WithPlaceholder(t *testing.T) {
 		assert.Equal(t, []any{18}, args)
 	})
 }
+
+func TestRestQL_WithDialect(t *testing.T) {
+	t.Parallel()
+
+	const query = "filter=age>18&fields=id,name&sort=-id&limit=10&offset=20"
+
+	tests := []struct {
+		name     string
+		opts     []restql.Option
+		expected string
+	}{
+		{
+			name:     "postgres",
+			opts:     []restql.Option{restql.WithDialect(restql.DialectPostgres)},
+			expected: `SELECT "id", "name" FROM "users" WHERE "age" > $1 ORDER BY "id" DESC LIMIT 10 OFFSET 20`,
+		},
+		{
+			name:     "mysql",
+			opts:     []restql.Option{restql.WithDialect(restql.DialectMySQL)},
+			expected: "SELECT `id`, `name` FROM `users` WHERE `age` > ? ORDER BY `id` DESC LIMIT 10 OFFSET 20",
+		},
+		{
+			name:     "sqlite",
+			opts:     []restql.Option{restql.WithDialect(restql.DialectSQLite)},
+			expected: `SELECT "id", "name" FROM "users" WHERE "age" > ? ORDER BY "id" DESC LIMIT 10 OFFSET 20`,
+		},
+		{
+			name:     "sql server",
+			opts:     []restql.Option{restql.WithDialect(restql.DialectSQLServer)},
+			expected: "SELECT [id], [name] FROM [users] WHERE [age] > @p1 ORDER BY [id] DESC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+		{
+			name:     "generic",
+			opts:     nil,
+			expected: "SELECT id, name FROM users WHERE age > ? ORDER BY id DESC LIMIT 10 OFFSET 20",
+		},
+		{
+			name: "individual options override the dialect",
+			opts: []restql.Option{
+				restql.WithPlaceholder("?"),
+				restql.WithUnquotedIdentifiers(),
+				restql.WithDialect(restql.DialectPostgres),
+			},
+			expected: "SELECT id, name FROM users WHERE age > ? ORDER BY id DESC LIMIT 10 OFFSET 20",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			params, err := url.ParseQuery(query)
+			require.NoError(t, err)
+
+			q, err := restql.NewRestQL(tc.opts...).Parse(params, "users")
+			require.NoError(t, err)
+
+			sql, args, err := q.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sql)
+			assert.Equal(t, []any{18}, args)
+		})
+	}
+
+	t.Run("sql server orders by a constant without a sort", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := url.ParseQuery("limit=5")
+		require.NoError(t, err)
+
+		q, err := restql.NewRestQL(restql.WithDialect(restql.DialectSQLServer)).Parse(params, "users")
+		require.NoError(t, err)
+
+		sql, _, err := q.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM [users] ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY", sql)
+	})
+
+	t.Run("postgres emits ILIKE natively", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := url.ParseQuery("filter=" + url.QueryEscape("name ILIKE 'jo%'"))
+		require.NoError(t, err)
+
+		q, err := restql.NewRestQL(restql.WithDialect(restql.DialectPostgres)).Parse(params, "users")
+		require.NoError(t, err)
+
+		sql, _, err := q.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "users" WHERE "name" ILIKE $1`, sql)
+	})
+}
+
+func TestRestQL_CombineFilters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AndFilters wraps OR operand", func(t *testing.T) {
+		t.Parallel()
+
+		user, err := restql.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+		policy, err := restql.ParseFilter("tenant_id=7 || role='admin'")
+		require.NoError(t, err)
+
+		qb := restql.NewQueryBuilder("users")
+		qb.SetFilter(restql.AndFilters(user, policy))
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE (age > ? AND status = ? AND (tenant_id = ? OR role = ?))", sql)
+		assert.Equal(t, []any{18, "active", 7, "admin"}, args)
+	})
+
+	t.Run("AndFilters wraps both OR operands", func(t *testing.T) {
+		t.Parallel()
+
+		user, err := restql.ParseFilter("status='active' || status='pending'")
+		require.NoError(t, err)
+		policy, err := restql.ParseFilter("tenant_id=7 || role='admin'")
+		require.NoError(t, err)
+
+		qb := restql.NewQueryBuilder("users")
+		qb.SetFilter(restql.AndFilters(user, policy))
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE ((status = ? OR status = ?) AND (tenant_id = ? OR role = ?))", sql)
+		assert.Equal(t, []any{"active", "pending", 7, "admin"}, args)
+	})
+
+	t.Run("OrFilters keeps AND groups together", func(t *testing.T) {
+		t.Parallel()
+
+		user, err := restql.ParseFilter("age>18 && status='active'")
+		require.NoError(t, err)
+		policy, err := restql.ParseFilter("role='admin' && verified=true")
+		require.NoError(t, err)
+
+		qb := restql.NewQueryBuilder("users")
+		qb.SetFilter(restql.OrFilters(user, policy))
+
+		sql, args, err := qb.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE ((age > ? AND status = ?) OR (role = ? AND verified = ?))", sql)
+		assert.Equal(t, []any{18, "active", "admin", true}, args)
+	})
+
+	t.Run("nil operand returns the other filter", func(t *testing.T) {
+		t.Parallel()
+
+		policy, err := restql.ParseFilter("tenant_id=7")
+		require.NoError(t, err)
+
+		assert.Same(t, policy, restql.AndFilters(nil, policy))
+		assert.Same(t, policy, restql.OrFilters(policy, nil))
+	})
+}
+
+func TestRestQL_FromRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GET request and JSON POST request produce equivalent SQL", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL(restql.WithPlaceholder("$1"))
+		opts := []restql.ValidateOption{
+			restql.WithAllowedFields([]string{"id", "name", "age"}),
+			restql.WithMaxLimit(100),
+		}
+
+		get := httptest.NewRequest(http.MethodGet, "/users?"+url.Values{
+			"filter": {"age>18"},
+			"fields": {"id,name"},
+			"sort":   {"-id"},
+			"limit":  {"10"},
+		}.Encode(), nil)
+
+		getQuery, err := rql.FromRequest(get, "users", opts...)
+		require.NoError(t, err)
+		getSQL, getArgs, err := getQuery.ToSQL()
+		require.NoError(t, err)
+
+		post := httptest.NewRequest(http.MethodPost, "/users/search", strings.NewReader(
+			`{"filter": "age>18", "fields": ["id", "name"], "sort": ["-id"], "limit": 10}`,
+		))
+		post.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		postQuery, err := rql.FromRequest(post, "users", opts...)
+		require.NoError(t, err)
+		postSQL, postArgs, err := postQuery.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT id, name FROM users WHERE age > $1 ORDER BY id DESC LIMIT 10", getSQL)
+		assert.Equal(t, getSQL, postSQL)
+		assert.Equal(t, getArgs, postArgs)
+	})
+
+	t.Run("POST without JSON content type reads the query string", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		req := httptest.NewRequest(http.MethodPost, "/users?filter=age>18", strings.NewReader("ignored"))
+
+		query, err := rql.FromRequest(req, "users")
+		require.NoError(t, err)
+		sql, _, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE age > ?", sql)
+	})
+
+	t.Run("invalid JSON body returns error", func(t *testing.T) {
+		t.Parallel()
+		rql := restql.NewRestQL()
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("{"))
+		req.Header.Set("Content-Type", "application/json")
+
+		_, err := rql.FromRequest(req, "users")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid JSON query body")
+	})
+}
+
+func TestRestQL_FromParams(t *testing.T) {
+	t.Parallel()
+
+	rql := restql.NewRestQL(restql.WithDialect(restql.DialectPostgres), restql.WithAllowedTables("users"))
+
+	t.Run("applies global configuration and validation", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := rql.FromParams(restql.QueryParams{Filter: "age>18", Limit: 500}, "users", restql.WithMaxLimit(100))
+		require.NoError(t, err)
+
+		_, _, err = query.ToSQL()
+		require.Error(t, err)
+
+		query, err = rql.FromParams(restql.QueryParams{Filter: "age>18", Sort: []string{"-id"}}, "users")
+		require.NoError(t, err)
+
+		sql, args, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "users" WHERE "age" > $1 ORDER BY "id" DESC`, sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("rejects disallowed tables", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := rql.FromParams(restql.QueryParams{}, "secrets")
+		require.Error(t, err)
+		assert.Nil(t, query)
+	})
+}
+
+func TestRestQL_NewPreset(t *testing.T) {
+	t.Parallel()
+
+	rql := restql.NewRestQL(restql.WithPlaceholder("$1"))
+	users := rql.NewPreset("users",
+		restql.WithAllowedFields([]string{"id", "name", "age"}),
+		restql.WithMaxLimit(100),
+	)
+
+	t.Run("valid requests share the preset", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []string{"filter=age>18&limit=10", "fields=id,name&sort=-id"} {
+			params, err := url.ParseQuery(raw)
+			require.NoError(t, err)
+
+			query, err := users.Parse(params)
+			require.NoError(t, err)
+
+			sql, _, err := query.ToSQL()
+			require.NoError(t, err)
+			assert.Contains(t, sql, "FROM users")
+		}
+	})
+
+	t.Run("every request is validated", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []string{"filter=password='x'", "limit=500"} {
+			params, err := url.ParseQuery(raw)
+			require.NoError(t, err)
+
+			query, err := users.Parse(params)
+			require.NoError(t, err)
+
+			_, _, err = query.ToSQL()
+			assert.Error(t, err, raw)
+		}
+	})
+
+	t.Run("FromRequest uses the preset", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/users?"+url.Values{"filter": {"age>18"}}.Encode(), nil)
+
+		query, err := users.FromRequest(req)
+		require.NoError(t, err)
+
+		sql, args, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE age > $1", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("NewQueryBuilder is bound to the preset", func(t *testing.T) {
+		t.Parallel()
+
+		qb := users.NewQueryBuilder()
+		qb.SetFilter(restql.F("age").Gt(18))
+		qb.SetLimit(10)
+
+		sql, args, err := users.Validate(qb).ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE age > $1 LIMIT 10", sql)
+		assert.Equal(t, []any{18}, args)
+	})
+
+	t.Run("NewQueryBuilder queries are validated", func(t *testing.T) {
+		t.Parallel()
+
+		qb := users.NewQueryBuilder()
+		qb.SetFields([]string{"id", "password"})
+
+		_, _, err := users.Validate(qb).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field 'password' is not allowed")
+	})
+}
+
+func TestRestQL_WithAllowedTables(t *testing.T) {
+	t.Parallel()
+
+	rql := restql.NewRestQL(restql.WithAllowedTables("users", "orders"))
+
+	t.Run("allowed table parses", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := url.ParseQuery("filter=total>100")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "orders")
+		require.NoError(t, err)
+
+		sql, _, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM orders WHERE total > ?", sql)
+	})
+
+	t.Run("allowed table is matched trimmed", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := rql.Parse(url.Values{}, " orders ")
+		require.NoError(t, err)
+
+		sql, _, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM orders", sql)
+	})
+
+	t.Run("disallowed table fails", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := rql.Parse(url.Values{}, "users; DROP TABLE users")
+		require.Error(t, err)
+		assert.Nil(t, query)
+		assert.Equal(t, "table 'users; DROP TABLE users' is not allowed", err.Error())
+	})
+
+	t.Run("disallowed table fails for JSON requests", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodPost, "/secrets", strings.NewReader(`{"limit": 10}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		_, err := rql.FromRequest(req, "secrets")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "table 'secrets' is not allowed")
+	})
+}
+
+func TestRestQL_WithStrictParams(t *testing.T) {
+	t.Parallel()
+
+	rql := restql.NewRestQL(restql.WithStrictParams("page_token"))
+
+	t.Run("typo'd parameters fail", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := url.ParseQuery("fitler=age>18&limt=10&sort=-id")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "users")
+		require.Error(t, err)
+		assert.Nil(t, query)
+		assert.Equal(t, "unknown query parameters: fitler, limt", err.Error())
+	})
+
+	t.Run("known and extra parameters pass", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := url.ParseQuery("filter=age>18&fields=id&sort=-id&limit=10&offset=20&page_token=abc")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "users")
+		require.NoError(t, err)
+
+		sql, _, err := query.ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM users WHERE age > ? ORDER BY id DESC LIMIT 10 OFFSET 20", sql)
+	})
+
+	t.Run("unknown parameters are ignored by default", func(t *testing.T) {
+		t.Parallel()
+
+		params, err := url.ParseQuery("fitler=age>18")
+		require.NoError(t, err)
+
+		_, err = restql.NewRestQL().Parse(params, "users")
+		require.NoError(t, err)
+	})
+}
+
+func TestRestQL_FilterDSL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		dsl    *restql.Filter
+		filter string
+	}{
+		{
+			name:   "AND",
+			dsl:    restql.F("age").Gt(18).And(restql.F("status").Eq("active")),
+			filter: "age>18 && status='active'",
+		},
+		{
+			name:   "OR inside AND",
+			dsl:    restql.F("age").Gte(18).And(restql.F("role").Eq("admin").Or(restql.F("verified").Eq(true))),
+			filter: "age>=18 && (role='admin' || verified=true)",
+		},
+		{
+			name:   "IN and NOT IN",
+			dsl:    restql.F("id").In(1, 2, 3).And(restql.F("status").NotIn([]string{"banned", "deleted"})),
+			filter: "id IN (1,2,3) && status NOT IN ('banned','deleted')",
+		},
+		{
+			name:   "NULL checks",
+			dsl:    restql.F("deleted_at").IsNull().Or(restql.F("restored_at").IsNotNull()),
+			filter: "deleted_at IS NULL || restored_at IS NOT NULL",
+		},
+		{
+			name:   "floats and LIKE",
+			dsl:    restql.F("price").Lt(9.5).And(restql.F("name").Like("%phone%")),
+			filter: "price<9.5 && name LIKE '%phone%'",
+		},
+		{
+			name:   "nil compares with NULL",
+			dsl:    restql.F("deleted_at").Eq(nil).And(restql.F("manager_id").Ne((*int)(nil))),
+			filter: "deleted_at = null && manager_id != null",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := restql.ParseFilter(tc.filter)
+			require.NoError(t, err)
+
+			wantSQL, wantArgs, err := restql.NewQueryBuilder("users").SetFilter(parsed).ToSQL()
+			require.NoError(t, err)
+
+			sql, args, err := restql.NewQueryBuilder("users").SetFilter(tc.dsl).ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, wantSQL, sql)
+			assert.Equal(t, wantArgs, args)
+		})
+	}
+
+	t.Run("values are bound, not inlined", func(t *testing.T) {
+		t.Parallel()
+
+		sql, args, err := restql.NewQueryBuilder("users").
+			SetFilter(restql.F("name").Eq("x' OR '1'='1")).
+			ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE name = ?", sql)
+		assert.Equal(t, []any{"x' OR '1'='1"}, args)
+	})
+
+	t.Run("empty IN matches nothing", func(t *testing.T) {
+		t.Parallel()
+
+		sql, args, err := restql.NewQueryBuilder("users").
+			SetFilter(restql.F("id").In([]int{})).
+			ToSQL()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM users WHERE 1 = 0", sql)
+		assert.Empty(t, args)
+	})
+
+	t.Run("unsupported value type is reported", func(t *testing.T) {
+		t.Parallel()
+
+		filter := restql.F("age").Gt(18).And(restql.F("id").Eq(struct{}{}))
+		require.Error(t, filter.Err())
+
+		_, _, err := restql.NewQueryBuilder("users").SetFilter(filter).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid value for field 'id': unsupported type struct {}")
+
+		_, _, err = restql.NewQueryBuilder("users").SetFilter(filter).Validate().ToSQL()
+		require.Error(t, err)
+
+		_, err = restql.ToMongoFilter(restql.F("tags").In(map[string]int{}))
+		require.Error(t, err)
+	})
+
+	t.Run("nil with an ordering operator is reported", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := restql.NewQueryBuilder("users").SetFilter(restql.F("age").Gt(nil)).ToSQL()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "operator > on field 'age' cannot compare with null")
+	})
+}
+
+func TestRestQL_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	params, err := url.ParseQuery("group=category&fields=category,COUNT(id) AS total&filter=price>10")
+	require.NoError(t, err)
+
+	query, err := restql.Parse(params, "products")
+	require.NoError(t, err)
+
+	sql, args, err := query.Validate(restql.WithAllowedFields([]string{"category", "id", "price"})).ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT category, COUNT(id) AS total FROM products WHERE price > ? GROUP BY category", sql)
+	assert.Equal(t, []any{10}, args)
+
+	params, err = url.ParseQuery("group=supplier&fields=supplier,COUNT(id) AS total")
+	require.NoError(t, err)
+
+	query, err = restql.Parse(params, "products")
+	require.NoError(t, err)
+
+	_, _, err = query.Validate(restql.WithAllowedFields([]string{"category", "id"})).ToSQL()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field 'supplier' is not allowed")
+}
+
+func TestRestQL_GroupByRollup(t *testing.T) {
+	t.Parallel()
+
+	params, err := url.ParseQuery("group=rollup:category,status&fields=category,status,SUM(total) AS revenue")
+	require.NoError(t, err)
+
+	query, err := restql.NewRestQL(restql.WithDialect(restql.DialectPostgres)).Parse(params, "orders",
+		restql.WithAllowedFields([]string{"category", "status", "total"}),
+	)
+	require.NoError(t, err)
+
+	sql, _, err := query.ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "category", "status", SUM("total") AS "revenue" FROM "orders" GROUP BY ROLLUP("category", "status")`, sql)
+
+	params, err = url.ParseQuery("group=ROLLUP:category,secret")
+	require.NoError(t, err)
+
+	query, err = restql.NewRestQL().Parse(params, "orders",
+		restql.WithAllowedFields([]string{"category", "status"}),
+	)
+	require.NoError(t, err)
+
+	_, _, err = query.ToSQL()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field 'secret' is not allowed")
+}
+
+func TestRestQL_WithUnboundedLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "limit=all", query: "limit=all&offset=20"},
+		{name: "limit=ALL", query: "limit=ALL&offset=20"},
+		{name: "limit=-1", query: "limit=-1&offset=20"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name+" with option emits no LIMIT", func(t *testing.T) {
+			t.Parallel()
+			rql := restql.NewRestQL()
+
+			params, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+
+			query, err := rql.Parse(params, "users", restql.WithUnboundedLimit(), restql.WithMaxLimit(100))
+			require.NoError(t, err)
+
+			sql, _, err := query.ToSQL()
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT * FROM users OFFSET 20", sql)
+		})
+
+		t.Run(tc.name+" without option is rejected", func(t *testing.T) {
+			t.Parallel()
+			rql := restql.NewRestQL()
+
+			params, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+
+			query, err := rql.Parse(params, "users", restql.WithMaxLimit(100))
+			require.NoError(t, err)
+
+			_, _, err = query.ToSQL()
+			require.Error(t, err)
+			assert.Equal(t, "unbounded limit is not allowed", err.Error())
+		})
+	}
+}
+
+func TestRestQL_TildeIsLike(t *testing.T) {
+	t.Parallel()
+
+	// Mirrors the README example
+	params, err := url.ParseQuery("filter=" + url.QueryEscape("name~'John%'"))
+	require.NoError(t, err)
+
+	query, err := restql.NewRestQL().Parse(params, "users")
+	require.NoError(t, err)
+
+	sql, args, err := query.ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name LIKE ?", sql)
+	assert.Equal(t, []any{"John%"}, args)
+}
+
+func TestRestQL_WithTildeILike(t *testing.T) {
+	t.Parallel()
+
+	params, err := url.ParseQuery("filter=" + url.QueryEscape("name~'jo%'"))
+	require.NoError(t, err)
+
+	query, err := restql.NewRestQL(restql.WithTildeILike(), restql.WithDialect(restql.DialectPostgres)).Parse(params, "users")
+	require.NoError(t, err)
+
+	sql, args, err := query.ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE "name" ILIKE $1`, sql)
+	assert.Equal(t, []any{"jo%"}, args)
+}
+
+func TestRestQL_FoldOrEqualsWithCaseFold(t *testing.T) {
+	t.Parallel()
+
+	params, err := url.ParseQuery("filter=" + url.QueryEscape("email='A' || email='B'"))
+	require.NoError(t, err)
+
+	query, err := restql.NewRestQL(restql.WithFoldOrEquals()).Parse(params, "users", restql.WithCaseFold("email"))
+	require.NoError(t, err)
+
+	sql, args, err := query.ToSQL()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (LOWER(email) = LOWER(?) OR LOWER(email) = LOWER(?))", sql)
+	assert.Equal(t, []any{"a", "b"}, args)
+}
+
+// capturingLogger records log entries as "LEVEL msg key=value ...".
+type capturingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *capturingLogger) Debug(msg string, args ...any) { l.log("DEBUG", msg, args) }
+func (l *capturingLogger) Warn(msg string, args ...any)  { l.log("WARN", msg, args) }
+
+func (l *capturingLogger) log(level, msg string, args []any) {
+	entry := level + " " + msg
+	for i := 0; i+1 < len(args); i += 2 {
+		entry += fmt.Sprintf(" %v=%v", args[i], args[i+1])
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func TestRestQL_WithLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("logs parsed params and generated SQL", func(t *testing.T) {
+		t.Parallel()
+		logger := &capturingLogger{}
+		rql := restql.NewRestQL(restql.WithLogger(logger))
+
+		params, err := url.ParseQuery("filter=age>18&limit=10")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "users", restql.WithMaxLimit(100))
+		require.NoError(t, err)
+		_, _, err = query.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"DEBUG restql: parsing query table=users params=map[filter:[age>18] limit:[10]]",
+			"DEBUG restql: built query table=users sql=SELECT * FROM users WHERE age > ? LIMIT 10 args=[18]",
+		}, logger.entries)
+	})
+
+	t.Run("logs validation rejection reason", func(t *testing.T) {
+		t.Parallel()
+		logger := &capturingLogger{}
+		rql := restql.NewRestQL(restql.WithLogger(logger))
+
+		params, err := url.ParseQuery("filter=password='x'")
+		require.NoError(t, err)
+
+		query, err := rql.Parse(params, "users", restql.WithForbiddenFields([]string{"password"}))
+		require.NoError(t, err)
+		_, _, err = query.ToSQL()
+		require.Error(t, err)
+
+		require.Len(t, logger.entries, 2)
+		assert.Equal(t, "WARN restql: query rejected table=users error=field 'password' is forbidden", logger.entries[1])
+	})
+
+	t.Run("logs parse rejection reason", func(t *testing.T) {
+		t.Parallel()
+		logger := &capturingLogger{}
+		rql := restql.NewRestQL(restql.WithLogger(logger), restql.WithAllowedTables("users"))
+
+		_, err := rql.Parse(url.Values{}, "secrets")
+		require.Error(t, err)
+
+		require.Len(t, logger.entries, 2)
+		assert.Equal(t, "WARN restql: query rejected table=secrets error=table 'secrets' is not allowed", logger.entries[1])
+	})
+}
+
+func TestRestQL_ReExportsMatchSubpackages(t *testing.T) {
+	t.Parallel()
+
+	const filter = "(age>=18 && (country='US' || country='CA')) || role='admin'"
+
+	t.Run("ParseFilter", func(t *testing.T) {
+		t.Parallel()
+
+		root, err := restql.ParseFilter(filter)
+		require.NoError(t, err)
+		sub, err := parser.ParseFilter(filter)
+		require.NoError(t, err)
+
+		assert.Equal(t, sub, root)
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		t.Parallel()
+
+		params := url.Values{"filter": {filter}, "sort": {"-age"}, "limit": {"10"}}
+
+		root, err := restql.Parse(params, "users")
+		require.NoError(t, err)
+		sub, err := query.Parse(params, "users")
+		require.NoError(t, err)
+
+		rootSQL, rootArgs, err := root.ToSQL()
+		require.NoError(t, err)
+		subSQL, subArgs, err := sub.ToSQL()
+		require.NoError(t, err)
+
+		assert.Equal(t, subSQL, rootSQL)
+		assert.Equal(t, subArgs, rootArgs)
+		assert.Equal(t, "SELECT * FROM users WHERE ((age >= ? AND (country = ? OR country = ?)) OR role = ?) ORDER BY age DESC LIMIT 10", rootSQL)
+	})
+}